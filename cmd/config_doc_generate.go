@@ -17,12 +17,14 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"go/ast"
 	"io"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -40,6 +42,7 @@ var (
 	rootTitle  = flag.String("root-title", "Root", "The title to use for the root struct block")
 	tag        = flag.String("tag", "yaml", "The struct tag to use for field names (e.g., yaml, json)")
 	title      = flag.String("title", "librarian.yaml", "The title of the generated Markdown page")
+	format     = flag.String("format", "markdown", "The output format: markdown or jsonschema")
 )
 
 const (
@@ -51,8 +54,28 @@ const (
 	// Markdown anchor components
 	anchorSuffix = "-configuration"
 	rootAnchor   = "root-configuration"
+
+	// JSON Schema components
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	defsPrefix      = "#/$defs/"
 )
 
+// enumValues lists the known values of string fields, keyed by struct name
+// and field name. They are emitted as enums in the JSON Schema.
+var enumValues = map[string][]string{
+	"Config.language":       {"dart", "go", "python", "rust"},
+	"Default.release_level": {"preview", "stable"},
+	"Default.transport":     {"grpc", "grpc+rest", "rest"},
+	"Library.release_level": {"preview", "stable"},
+	"Library.transport":     {"grpc", "grpc+rest", "rest"},
+	"RustModule.template":   {"convert-prost", "grpc-client", "http-client", "mod", "prost"},
+}
+
+// externalTypes maps types from other packages to their JSON Schema.
+var externalTypes = map[string]*jsonSchema{
+	"yaml.StringSlice": {Type: "array", Items: &jsonSchema{Type: "string"}},
+}
+
 var docTemplate = template.Must(template.New("doc").Parse(`# {{.Title}} Schema
 
 This document describes the schema for the {{.Title}}.
@@ -84,6 +107,20 @@ type fieldData struct {
 	Description string
 }
 
+// jsonSchema is the subset of JSON Schema used to describe config structs.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
 // main is the entry point for the config doc generator tool.
 // It scans Go source files for struct definitions and extracts YAML tags, types,
 // and doc comments to produce a schema document for librarian.yaml.
@@ -113,7 +150,15 @@ func run() (err error) {
 	if err != nil {
 		return fmt.Errorf("inspecting package syntax: %w", err)
 	}
-	if err := d.generate(output); err != nil {
+	switch *format {
+	case "markdown":
+		err = d.generate(output)
+	case "jsonschema":
+		err = d.generateSchema(output)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
 		return fmt.Errorf("generating documentation: %w", err)
 	}
 	return nil
@@ -280,6 +325,104 @@ func (d *docData) collectStructData(name string, sourceLink string) (structData,
 	return structData, nil
 }
 
+// generateSchema writes a JSON Schema describing the root struct and every
+// struct it refers to.
+func (d *docData) generateSchema(output io.Writer) error {
+	schema := &jsonSchema{
+		Schema: jsonSchemaDraft,
+		Title:  d.title,
+		Ref:    defsPrefix + d.rootStruct,
+		Defs:   make(map[string]*jsonSchema),
+	}
+	for _, k := range append(d.configKeys, d.otherKeys...) {
+		schema.Defs[k] = d.structSchema(k)
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = output.Write(append(data, '\n'))
+	return err
+}
+
+// structSchema returns the JSON Schema of a single Go struct. Fields of
+// embedded structs are included as properties of the struct itself.
+func (d *docData) structSchema(name string) *jsonSchema {
+	schema := &jsonSchema{
+		Type:                 "object",
+		Description:          schemaDoc(d.docs[name]),
+		Properties:           make(map[string]*jsonSchema),
+		AdditionalProperties: false,
+	}
+	for _, field := range d.structs[name].Fields.List {
+		if len(field.Names) == 0 {
+			embedded := strings.TrimPrefix(getTypeName(field.Type), "*")
+			if _, ok := d.structs[embedded]; ok {
+				maps.Copy(schema.Properties, d.structSchema(embedded).Properties)
+			}
+			continue
+		}
+		fieldName := d.getFieldName(field)
+		if fieldName == "" || fieldName == "-" {
+			continue
+		}
+		prop := d.typeSchema(field.Type)
+		if enum, ok := enumValues[name+"."+fieldName]; ok {
+			prop.Enum = enum
+		}
+		if field.Doc != nil {
+			prop.Description = schemaDoc(field.Doc.Text())
+		}
+		schema.Properties[fieldName] = prop
+	}
+	return schema
+}
+
+// typeSchema returns the JSON Schema of a field type. Types that cannot be
+// described accept any value.
+func (d *docData) typeSchema(expr ast.Expr) *jsonSchema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return &jsonSchema{Type: "string"}
+		case "bool":
+			return &jsonSchema{Type: "boolean"}
+		case "int", "int32", "int64", "uint", "uint32", "uint64":
+			return &jsonSchema{Type: "integer"}
+		case "float32", "float64":
+			return &jsonSchema{Type: "number"}
+		}
+		if _, ok := d.structs[t.Name]; ok {
+			return &jsonSchema{Ref: defsPrefix + t.Name}
+		}
+	case *ast.StarExpr:
+		return d.typeSchema(t.X)
+	case *ast.ArrayType:
+		return &jsonSchema{Type: "array", Items: d.typeSchema(t.Elt)}
+	case *ast.MapType:
+		return &jsonSchema{Type: "object", AdditionalProperties: d.typeSchema(t.Value)}
+	case *ast.SelectorExpr:
+		if s, ok := externalTypes[getTypeName(t)]; ok {
+			copied := *s
+			return &copied
+		}
+	}
+	return &jsonSchema{}
+}
+
+// schemaDoc collapses standard word-wrapping in a doc comment into single
+// spaces, keeping paragraph breaks.
+func schemaDoc(doc string) string {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.TrimSpace(doc), "\n\n") {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
 // getFieldName returns the documentation name for a field. It first attempts to
 // extract the name from the struct tag specified by the tagName field. If the
 // tag is missing or empty, it falls back to the Go field name.
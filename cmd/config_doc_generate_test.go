@@ -17,11 +17,18 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"go/ast"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/yaml"
 )
 
 func TestGetFieldName(t *testing.T) {
@@ -318,3 +325,232 @@ type Alpha struct {
 		t.Error("output missing expected source link for Config")
 	}
 }
+
+func TestGenerateSchema(t *testing.T) {
+	dir := t.TempDir()
+	configContent := `
+package config
+// Config doc
+type Config struct {
+	// Language doc
+	Language string ` + "`" + `yaml:"language"` + "`" + `
+	Libraries []*Library ` + "`" + `yaml:"libraries,omitempty"` + "`" + `
+	Env map[string]string ` + "`" + `yaml:"env,omitempty"` + "`" + `
+	Skipped string ` + "`" + `yaml:"-"` + "`" + `
+}
+// Library doc
+type Library struct {
+	Count int ` + "`" + `yaml:"count"` + "`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module config\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := newDocData(pkg, "Config", "Root", "yaml", "librarian.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	if err := d.generateSchema(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"$schema": jsonSchemaDraft,
+		"$ref":    "#/$defs/Config",
+		"title":   "librarian.yaml",
+		"$defs": map[string]any{
+			"Config": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"language": map[string]any{
+						"type":        "string",
+						"description": "Language doc",
+						"enum":        []any{"dart", "go", "python", "rust"},
+					},
+					"libraries": map[string]any{
+						"type":  "array",
+						"items": map[string]any{"$ref": "#/$defs/Library"},
+					},
+					"env": map[string]any{
+						"type":                 "object",
+						"additionalProperties": map[string]any{"type": "string"},
+					},
+				},
+				"additionalProperties": false,
+			},
+			"Library": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"count": map[string]any{"type": "integer"},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSchemaRoundTrip(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "doc", "librarian.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema jsonSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name    string
+		modify  func(*config.Config)
+		wantErr bool
+	}{
+		{
+			name: "sample config",
+		},
+		{
+			name: "invalid language",
+			modify: func(cfg *config.Config) {
+				cfg.Language = "cobol"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid release level",
+			modify: func(cfg *config.Config) {
+				cfg.Libraries[0].ReleaseLevel = "beta"
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := sample.Config()
+			cfg.Language = "rust"
+			cfg.Default.Transport = "grpc+rest"
+			cfg.Default.ReleaseLevel = "stable"
+			if test.modify != nil {
+				test.modify(cfg)
+			}
+			content, err := yaml.Marshal(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, err := yaml.Unmarshal[map[string]any](content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = validateSchema(&schema, &schema, *value, "")
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("validateSchema() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestSchemaRejectsUnknownField(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "doc", "librarian.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema jsonSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	value := map[string]any{"language": "go", "not_a_field": true}
+	if err := validateSchema(&schema, &schema, value, ""); err == nil {
+		t.Errorf("validateSchema() succeeded, want error")
+	}
+}
+
+// validateSchema checks value against the subset of JSON Schema emitted by
+// generateSchema.
+func validateSchema(root, schema *jsonSchema, value any, path string) error {
+	if schema.Ref != "" {
+		def, ok := root.Defs[strings.TrimPrefix(schema.Ref, defsPrefix)]
+		if !ok {
+			return fmt.Errorf("%s: unknown reference %q", path, schema.Ref)
+		}
+		return validateSchema(root, def, value, path)
+	}
+	if len(schema.Enum) > 0 {
+		s, _ := value.(string)
+		found := false
+		for _, e := range schema.Enum {
+			found = found || e == s
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, value, schema.Enum)
+		}
+	}
+	switch schema.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: want string, got %T", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: want boolean, got %T", path, value)
+		}
+	case "integer":
+		if _, ok := value.(int); !ok {
+			return fmt.Errorf("%s: want integer, got %T", path, value)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: want array, got %T", path, value)
+		}
+		for i, item := range items {
+			if err := validateSchema(root, schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		m, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: want object, got %T", path, value)
+		}
+		for k, v := range m {
+			prop, ok := schema.Properties[k]
+			if !ok {
+				switch additional := schema.AdditionalProperties.(type) {
+				case bool:
+					return fmt.Errorf("%s: unknown field %q", path, k)
+				case map[string]any:
+					data, err := json.Marshal(additional)
+					if err != nil {
+						return err
+					}
+					prop = &jsonSchema{}
+					if err := json.Unmarshal(data, prop); err != nil {
+						return err
+					}
+				default:
+					continue
+				}
+			}
+			if err := validateSchema(root, prop, v, path+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
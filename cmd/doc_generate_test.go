@@ -43,6 +43,10 @@ func TestGoGenerateLibrarianDoc(t *testing.T) {
 			name:    "config_schema_doc",
 			docFile: "doc/config-schema.md",
 		},
+		{
+			name:    "config_json_schema",
+			docFile: "doc/librarian.schema.json",
+		},
 		{
 			name:    "service_config_schema_doc",
 			docFile: "doc/api-allowlist-schema.md",
@@ -41,8 +41,11 @@ OPTIONS:
 
 GLOBAL OPTIONS:
 
-	--force, -f    skip binary version check
-	--verbose, -v  enable verbose logging
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
 
 # generate
 
@@ -52,17 +55,26 @@ NAME:
 
 USAGE:
 
-	librarian generate [library] [--all]
+	librarian generate [library] [--all] [--libraries-from file] [--force-generate]
 
 OPTIONS:
 
-	--all       generate all libraries
-	--help, -h  show help
+	--all                    generate all libraries
+	--libraries-from string  generate the libraries named in this file, one per line; use - to read from stdin
+	--force-generate         generate the named libraries even if they have skip_generate set; has no effect with --all
+	--no-format              skip formatting the generated code; Python code is formatted by its post processor and is always formatted
+	--no-clean               generate on top of the existing output without cleaning it first; files the generator no longer produces are left behind
+	--cpuprofile string      write a pprof CPU profile of the run to this file
+	--memprofile string      write a pprof memory profile at the end of the run to this file
+	--help, -h               show help
 
 GLOBAL OPTIONS:
 
-	--force, -f    skip binary version check
-	--verbose, -v  enable verbose logging
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
 
 # bump
 
@@ -79,8 +91,13 @@ DESCRIPTION:
 	bump updates version numbers and prepares the files needed for a new release.
 
 	If a library name is given, only that library is updated. The --all flag updates every
-	library in the workspace. When a library is specified explicitly, the --version flag can
-	be used to override the new version.
+	library in the workspace. When a library is specified explicitly, the --version flag, or its
+	alias --set, can be used to override the new version.
+
+	Otherwise the new version is derived from the commits that changed the library
+	since the last release, following Conventional Commits: a breaking change
+	bumps the major version, a "feat" commit bumps the minor version, and any other
+	change bumps the patch version.
 
 	Examples:
 	  librarian bump <library>           # update version for one library
@@ -88,14 +105,155 @@ DESCRIPTION:
 
 OPTIONS:
 
-	--all             update all libraries in the workspace
-	--version string  specific version to update to; not valid with --all
-	--help, -h        show help
+	--all                           update all libraries in the workspace
+	--version string, --set string  specific version to update to; not valid with --all
+	--help, -h                      show help
+
+GLOBAL OPTIONS:
+
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
+
+# metadata
+
+NAME:
+
+	librarian metadata - regenerate package metadata files of a client library
+
+USAGE:
+
+	librarian metadata [library] [--all]
+
+DESCRIPTION:
+
+	metadata refreshes the package metadata files of a library, such as
+	setup.py and pyproject.toml for Python, from librarian.yaml without
+	regenerating the library. The version, description and release level are
+	taken from the library configuration.
+
+	Examples:
+	  librarian metadata <library>       # refresh metadata for one library
+	  librarian metadata --all           # refresh metadata for all libraries
+
+OPTIONS:
+
+	--all       refresh metadata for all libraries
+	--help, -h  show help
+
+GLOBAL OPTIONS:
+
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
+
+# package
+
+NAME:
+
+	librarian package - create a reproducible archive of a library's output
+
+USAGE:
+
+	librarian package <library> [-o <file>]
+
+DESCRIPTION:
+
+	package writes the generated files of a library to a gzip-compressed tar
+	archive. For Go, whose libraries share the repository root, these are the files
+	in the directory named after the library. Entries are sorted and written with
+	fixed ownership, permissions and modification times, so packaging the same files
+	twice produces byte-identical archives. The modification time is taken from
+	SOURCE_DATE_EPOCH when it is set, and is the Unix epoch otherwise.
+
+	Examples:
+	  librarian package <library>                 # writes <library>.tar.gz
+	  librarian package <library> -o out.tar.gz
+
+OPTIONS:
+
+	-o file     write the archive to file (default <library>.tar.gz)
+	--help, -h  show help
+
+GLOBAL OPTIONS:
+
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
+
+# verify-golden
+
+NAME:
+
+	librarian verify-golden - compare a freshly generated library with a golden archive
+
+USAGE:
+
+	librarian verify-golden <library> --golden <file>
+
+DESCRIPTION:
+
+	verify-golden generates a library and compares its output directory with a
+	golden archive, such as one written by "librarian package". Every file that is
+	missing, unexpected or has different content is reported, and the command
+	fails if there is any difference.
+
+	Examples:
+	  librarian package <library> -o golden.tar.gz
+	  librarian verify-golden <library> --golden golden.tar.gz
+
+OPTIONS:
+
+	--golden file  the golden file, a gzip-compressed tar archive
+	--help, -h     show help
 
 GLOBAL OPTIONS:
 
-	--force, -f    skip binary version check
-	--verbose, -v  enable verbose logging
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
+
+# verify-versions
+
+NAME:
+
+	librarian verify-versions - check that generated code embeds the configured library versions
+
+USAGE:
+
+	librarian verify-versions [library]
+
+DESCRIPTION:
+
+	verify-versions compares the version of each library in librarian.yaml with
+	the version embedded in its generated code, and fails if any of them differ.
+	This happens when a version is changed in librarian.yaml without regenerating
+	the library. If a library name is given, only that library is checked.
+	Libraries without a version are skipped, and a library with a version whose
+	generated code embeds none is reported as drifted.
+
+	Only Go libraries are supported, whose version is the Version constant in
+	<library>/internal/version.go.
+
+OPTIONS:
+
+	--help, -h  show help
+
+GLOBAL OPTIONS:
+
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
 
 # tidy
 
@@ -105,16 +263,27 @@ NAME:
 
 USAGE:
 
-	librarian tidy [path]
+	librarian tidy [path] [--fix]
+
+DESCRIPTION:
+
+	tidy formats and validates librarian.yaml. It also reports directories
+	under the default output root that belong to no configured library, such as
+	the output of a library that was removed from librarian.yaml. Paths listed in
+	a library's keep list and .git directories are never reported.
 
 OPTIONS:
 
+	--fix       remove output directories that belong to no library
 	--help, -h  show help
 
 GLOBAL OPTIONS:
 
-	--force, -f    skip binary version check
-	--verbose, -v  enable verbose logging
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
 
 # update
 
@@ -124,7 +293,7 @@ NAME:
 
 USAGE:
 
-	librarian update [--all | source]
+	librarian update [--all | source] [--generate] [--since commit]
 
 DESCRIPTION:
 
@@ -135,15 +304,31 @@ DESCRIPTION:
 	  - protobuf
 	  - showcase
 
+	With --generate, libraries whose APIs changed in googleapis since they were
+	last generated are regenerated. If sources.googleapis.dir is set, changes are
+	found in the git history of that checkout. Otherwise, the API directories of
+	the pinned googleapis commit are compared with those of the commit each
+	library was last generated from. That commit is recorded for each library in
+	librarian-state.yaml.
+
+	With --since, changes are instead found since the given googleapis commit for
+	every library, which is useful to backfill from a known-good point. It implies
+	--generate and requires sources.googleapis.dir.
+
 OPTIONS:
 
-	--all       update discovery and googleapis sources
-	--help, -h  show help
+	--all           update discovery and googleapis sources
+	--generate      regenerate only the libraries whose APIs changed since they were last generated
+	--since string  with --generate, find API changes since this googleapis commit instead of since each library was last generated
+	--help, -h      show help
 
 GLOBAL OPTIONS:
 
-	--force, -f    skip binary version check
-	--verbose, -v  enable verbose logging
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
 
 # version
 
@@ -161,8 +346,11 @@ OPTIONS:
 
 GLOBAL OPTIONS:
 
-	--force, -f    skip binary version check
-	--verbose, -v  enable verbose logging
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
 
 # publish
 
@@ -176,7 +364,7 @@ USAGE:
 
 OPTIONS:
 
-	--execute             fully publish (default is to only perform a dry run)
+	--execute             fully publish (default is to only perform a dry run, printing the tags and release notes that would be created)
 	--library string      library to find a release commit for; default finds latest release commit for any library
 	--dry-run             print commands without executing (legacy Rust-only flag)
 	--dry-run-keep-going  print commands without executing, don't stop on error (legacy Rust-only flag)
@@ -185,7 +373,10 @@ OPTIONS:
 
 GLOBAL OPTIONS:
 
-	--force, -f    skip binary version check
-	--verbose, -v  enable verbose logging
+	--force, -f          skip binary version check
+	--verbose, -v        enable verbose logging
+	--config string      path to the librarian configuration file; paths in it are relative to its directory (default: "librarian.yaml")
+	--log-level string   minimum level of log messages: debug, info, warn or error (default: "info")
+	--log-format string  format of log messages: text or json (default: "text")
 */
 package main
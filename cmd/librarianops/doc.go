@@ -23,6 +23,43 @@ Usage:
 
 The commands are:
 
+# add-api
+
+NAME:
+
+	librarianops add-api - print a new API allowlist entry
+
+USAGE:
+
+	librarianops add-api --googleapis <dir> --path <api-path>
+
+DESCRIPTION:
+
+	Reads the service config and BUILD.bazel of the API at path and prints an
+	entry for the APIs slice in internal/serviceconfig/api.go, with the service
+	name and the transport of each language filled in.
+
+OPTIONS:
+
+	--googleapis directory  googleapis directory
+	--path path             API path in googleapis, such as google/cloud/secretmanager/v1
+	--help, -h              show help
+
+# dump-allowlist
+
+NAME:
+
+	librarianops dump-allowlist - print the API allowlist
+
+USAGE:
+
+	librarianops dump-allowlist [--format md]
+
+OPTIONS:
+
+	--format format  output format; only md (Markdown) is supported (default: "md")
+	--help, -h       show help
+
 # generate
 
 NAME:
@@ -58,5 +95,26 @@ OPTIONS:
 	-C directory  work in directory (repo name inferred from basename)
 	-v            run librarian with verbose output
 	--help, -h    show help
+
+# validate-allowlist
+
+NAME:
+
+	librarianops validate-allowlist - check that files referenced by the API allowlist exist
+
+USAGE:
+
+	librarianops validate-allowlist [--googleapis <dir>] [--discovery <dir>] [--testdata <dir>]
+
+DESCRIPTION:
+
+	References relative to a root that is not specified are not checked. Languages whose transport for an API breaks a transport consistency rule are logged as warnings.
+
+OPTIONS:
+
+	--googleapis directory  googleapis directory, the root of ServiceConfig paths
+	--discovery directory   discovery-artifact-manager directory, the root of Discovery paths
+	--testdata directory    directory that is the root of OpenAPI paths, such as internal in this repository
+	--help, -h              show help
 */
 package main
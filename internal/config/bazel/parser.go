@@ -16,18 +16,32 @@
 package bazel
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/bazelbuild/buildtools/build"
 )
 
+// selectDefaultCondition is the select() key used when no other condition
+// matches.
+const selectDefaultCondition = "//conditions:default"
+
+var errUnsupportedExpr = errors.New("unsupported expression")
+
 // Config holds configuration extracted from googleapis BUILD.bazel files.
 type Config struct {
 	// DIREGAPIC indicates whether DIREGAPIC (Discovery REST GAPICs) is used.
 	DIREGAPIC bool
 
+	// DIREGAPICProtos lists the proto files, relative to the BUILD.bazel
+	// directory, that a DIREGAPIC go_gapic_library rule is built from. It is
+	// empty unless DIREGAPIC is true.
+	DIREGAPICProtos []string
+
 	// GAPICImportPath is the import path for the GAPIC library.
 	GAPICImportPath string
 
@@ -63,39 +77,104 @@ type Config struct {
 }
 
 // Parse reads a BUILD.bazel file and extracts configuration from Bazel rules.
+// If the file declares more than one go_gapic_library rule, only the first is
+// used; see ParseAll.
 func Parse(path string) (*Config, error) {
+	f, base, err := parseBuild(path)
+	if err != nil {
+		return nil, err
+	}
+	rules := f.Rules("go_gapic_library")
+	if len(rules) == 0 {
+		return base, nil
+	}
+	return gapicConfig(path, f, rules[0], base)
+}
+
+// ParseAll reads a BUILD.bazel file and returns one Config per
+// go_gapic_library rule, in the order they are declared. Most BUILD.bazel
+// files have a single GAPIC rule, but a directory may declare several, for
+// example when a service is split into more than one Go package. ParseAll
+// returns an empty slice if the file has no GAPIC rules.
+func ParseAll(path string) ([]*Config, error) {
+	f, base, err := parseBuild(path)
+	if err != nil {
+		return nil, err
+	}
+	var configs []*Config
+	for _, rule := range f.Rules("go_gapic_library") {
+		cfg, err := gapicConfig(path, f, rule, base)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// gapicRuleLanguages maps the prefix of a GAPIC rule kind, such as "py" in
+// py_gapic_library, to the language of the generated library.
+var gapicRuleLanguages = map[string]string{
+	"csharp":     "dotnet",
+	"go":         "go",
+	"java":       "java",
+	"nodejs":     "nodejs",
+	"php":        "php",
+	"py":         "python",
+	"ruby_cloud": "ruby",
+}
+
+// Transports reads a BUILD.bazel file and returns the transport declared by
+// the GAPIC rule of each language, keyed by language name, such as
+// {"go": "grpc+rest", "python": "grpc"}. Rules without a transport attribute
+// and rules for unknown languages are ignored.
+func Transports(path string) (map[string]string, error) {
+	f, _, err := parseBuild(path)
+	if err != nil {
+		return nil, err
+	}
+	transports := make(map[string]string)
+	for _, rule := range f.Rules("") {
+		prefix, ok := strings.CutSuffix(rule.Kind(), "_gapic_library")
+		if !ok {
+			continue
+		}
+		language, ok := gapicRuleLanguages[prefix]
+		if !ok {
+			continue
+		}
+		if transport := rule.AttrString("transport"); transport != "" {
+			transports[language] = transport
+		}
+	}
+	return transports, nil
+}
+
+// parseBuild parses the BUILD.bazel file at path and returns it together with
+// the configuration derived from its proto and gRPC rules, which is shared by
+// every GAPIC rule in the file.
+func parseBuild(path string) (*build.File, *Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read BUILD.bazel file %s: %w", path, err)
+		return nil, nil, fmt.Errorf("failed to read BUILD.bazel file %s: %w", path, err)
 	}
 	f, err := build.ParseBuild(path, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse BUILD.bazel file %s: %w", path, err)
+		return nil, nil, fmt.Errorf("failed to parse BUILD.bazel file %s: %w", path, err)
 	}
 
 	cfg := &Config{}
-	if rules := f.Rules("go_gapic_library"); len(rules) > 0 {
-		rule := rules[0]
-		cfg = &Config{
-			HasGAPIC:          true,
-			GRPCServiceConfig: rule.AttrString("grpc_service_config"),
-			GAPICImportPath:   rule.AttrString("importpath"),
-			ReleaseLevel:      rule.AttrString("release_level"),
-			ServiceYAML:       strings.TrimPrefix(rule.AttrString("service_yaml"), ":"),
-			Transport:         rule.AttrString("transport"),
-			Metadata:          rule.AttrLiteral("metadata") == "True",
-			RESTNumericEnums:  rule.AttrLiteral("rest_numeric_enums") == "True",
-			DIREGAPIC:         rule.AttrLiteral("diregapic") == "True",
-		}
-	}
 	if len(f.Rules("go_grpc_library")) > 0 {
 		cfg.HasGoGRPC = true
 	}
 	if rules := f.Rules("go_proto_library"); len(rules) > 0 {
 		if cfg.HasGoGRPC {
-			return nil, fmt.Errorf("BUILD.bazel cannot have both go_grpc_library and go_proto_library: %s", path)
+			return nil, nil, fmt.Errorf("BUILD.bazel cannot have both go_grpc_library and go_proto_library: %s", path)
+		}
+		compilers, err := attrStrings(rules[0], "compilers")
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
 		}
-		compilers := rules[0].AttrStrings("compilers")
 		for _, compiler := range compilers {
 			if strings.Contains(compiler, "@io_bazel_rules_go//proto:go_grpc") {
 				cfg.HasLegacyGRPC = true
@@ -103,13 +182,148 @@ func Parse(path string) (*Config, error) {
 			}
 		}
 	}
-	if cfg.HasGAPIC {
-		if cfg.GAPICImportPath == "" {
-			return nil, fmt.Errorf("GAPICImportPath not set: %s", path)
-		}
-		if cfg.ServiceYAML == "" {
-			return nil, fmt.Errorf("ServiceYAML not set: %s", path)
+	return f, cfg, nil
+}
+
+// gapicConfig returns the configuration for a go_gapic_library rule, combined
+// with the file-wide settings in base.
+func gapicConfig(path string, f *build.File, rule *build.Rule, base *Config) (*Config, error) {
+	cfg := &Config{
+		HasGAPIC:          true,
+		GRPCServiceConfig: rule.AttrString("grpc_service_config"),
+		GAPICImportPath:   rule.AttrString("importpath"),
+		ReleaseLevel:      rule.AttrString("release_level"),
+		ServiceYAML:       strings.TrimPrefix(rule.AttrString("service_yaml"), ":"),
+		Transport:         rule.AttrString("transport"),
+		Metadata:          rule.AttrLiteral("metadata") == "True",
+		RESTNumericEnums:  rule.AttrLiteral("rest_numeric_enums") == "True",
+		DIREGAPIC:         rule.AttrLiteral("diregapic") == "True",
+		HasGoGRPC:         base.HasGoGRPC,
+		HasLegacyGRPC:     base.HasLegacyGRPC,
+	}
+	if cfg.GAPICImportPath == "" {
+		return nil, fmt.Errorf("GAPICImportPath not set: %s", path)
+	}
+	if cfg.ServiceYAML == "" {
+		return nil, fmt.Errorf("ServiceYAML not set: %s", path)
+	}
+	if cfg.DIREGAPIC {
+		protos, err := protoSources(f, rule)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
 		}
+		cfg.DIREGAPICProtos = protos
 	}
 	return cfg, nil
 }
+
+// protoSources returns the sorted .proto files that rule depends on, following
+// the srcs, deps and protos attributes through the rules declared in f.
+// Labels that refer to other packages, such as common resources, are not
+// followed.
+func protoSources(f *build.File, rule *build.Rule) ([]string, error) {
+	rules := make(map[string]*build.Rule)
+	for _, r := range f.Rules("") {
+		rules[r.Name()] = r
+	}
+	files := make(map[string]bool)
+	visited := make(map[string]bool)
+	var visit func(r *build.Rule) error
+	visit = func(r *build.Rule) error {
+		if visited[r.Name()] {
+			return nil
+		}
+		visited[r.Name()] = true
+		for _, attr := range []string{"srcs", "deps", "protos"} {
+			values, err := attrStrings(r, attr)
+			if err != nil {
+				return err
+			}
+			for _, value := range values {
+				switch {
+				case strings.HasPrefix(value, ":"):
+					if dep, ok := rules[strings.TrimPrefix(value, ":")]; ok {
+						if err := visit(dep); err != nil {
+							return err
+						}
+					}
+				case strings.HasSuffix(value, ".proto") && !strings.Contains(value, ":"):
+					files[value] = true
+				}
+			}
+		}
+		return nil
+	}
+	if err := visit(rule); err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(files)), nil
+}
+
+// attrStrings returns the strings of a list-valued attribute. Unlike
+// build.Rule.AttrStrings, it also understands lists joined with + and
+// select() expressions, which resolve to their //conditions:default branch.
+// It returns nil if the attribute is not set.
+func attrStrings(rule *build.Rule, name string) ([]string, error) {
+	expr := rule.Attr(name)
+	if expr == nil {
+		return nil, nil
+	}
+	values, err := flattenStrings(expr)
+	if err != nil {
+		return nil, fmt.Errorf("attribute %q of rule %q: %w", name, rule.Name(), err)
+	}
+	return values, nil
+}
+
+func flattenStrings(expr build.Expr) ([]string, error) {
+	switch e := expr.(type) {
+	case *build.StringExpr:
+		return []string{e.Value}, nil
+	case *build.ListExpr:
+		var values []string
+		for _, item := range e.List {
+			s, ok := item.(*build.StringExpr)
+			if !ok {
+				return nil, fmt.Errorf("%w: list element %s", errUnsupportedExpr, build.FormatString(item))
+			}
+			values = append(values, s.Value)
+		}
+		return values, nil
+	case *build.BinaryExpr:
+		if e.Op != "+" {
+			return nil, fmt.Errorf("%w: operator %q", errUnsupportedExpr, e.Op)
+		}
+		x, err := flattenStrings(e.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := flattenStrings(e.Y)
+		if err != nil {
+			return nil, err
+		}
+		return append(x, y...), nil
+	case *build.CallExpr:
+		return selectDefault(e)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedExpr, build.FormatString(expr))
+	}
+}
+
+// selectDefault returns the strings of the //conditions:default branch of a
+// select() call.
+func selectDefault(call *build.CallExpr) ([]string, error) {
+	if fn, ok := call.X.(*build.Ident); !ok || fn.Name != "select" || len(call.List) != 1 {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedExpr, build.FormatString(call))
+	}
+	dict, ok := call.List[0].(*build.DictExpr)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedExpr, build.FormatString(call))
+	}
+	for _, kv := range dict.List {
+		if key, ok := kv.Key.(*build.StringExpr); ok && key.Value == selectDefaultCondition {
+			return flattenStrings(kv.Value)
+		}
+	}
+	return nil, fmt.Errorf("%w: select() without a %q branch", errUnsupportedExpr, selectDefaultCondition)
+}
@@ -15,6 +15,7 @@
 package bazel
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -263,6 +264,320 @@ go_gapic_library(
 	}
 }
 
+func TestParseAll(t *testing.T) {
+	buildPath := writeBuild(t, `
+go_grpc_library(
+    name = "speech_go_proto",
+    importpath = "cloud.google.com/go/speech/apiv1/speechpb",
+    protos = [":speech_proto"],
+)
+
+go_gapic_library(
+    name = "speech_go_gapic",
+    importpath = "cloud.google.com/go/speech/apiv1;speech",
+    service_yaml = "speech_v1.yaml",
+    transport = "grpc+rest",
+)
+
+go_gapic_library(
+    name = "adaptation_go_gapic",
+    importpath = "cloud.google.com/go/speech/apiv1/adaptation;adaptation",
+    service_yaml = "speech_v1.yaml",
+    transport = "grpc",
+)
+`)
+	got, err := ParseAll(buildPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Config{
+		{
+			HasGAPIC:        true,
+			GAPICImportPath: "cloud.google.com/go/speech/apiv1;speech",
+			ServiceYAML:     "speech_v1.yaml",
+			Transport:       "grpc+rest",
+			HasGoGRPC:       true,
+		},
+		{
+			HasGAPIC:        true,
+			GAPICImportPath: "cloud.google.com/go/speech/apiv1/adaptation;adaptation",
+			ServiceYAML:     "speech_v1.yaml",
+			Transport:       "grpc",
+			HasGoGRPC:       true,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// Parse keeps returning only the first rule.
+	first, err := Parse(buildPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want[0], first); diff != "" {
+		t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseAll_NoGAPIC(t *testing.T) {
+	got, err := ParseAll(writeBuild(t, `go_grpc_library()`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("ParseAll() = %v, want no configs", got)
+	}
+}
+
+func TestParseAll_Error(t *testing.T) {
+	buildPath := writeBuild(t, `
+go_gapic_library(
+    importpath = "cloud.google.com/go/test",
+    service_yaml = "test.yaml",
+)
+
+go_gapic_library(
+    service_yaml = "test.yaml",
+)
+`)
+	if _, err := ParseAll(buildPath); err == nil {
+		t.Error("ParseAll() succeeded; want error")
+	}
+}
+
+func TestParseAll_DIREGAPICProtos(t *testing.T) {
+	buildPath := writeBuild(t, `
+proto_library(
+    name = "compute_proto",
+    srcs = ["compute.proto"],
+    deps = ["//google/api:annotations_proto"],
+)
+
+proto_library(
+    name = "operations_proto",
+    srcs = [
+        "operations.proto",
+        "status.proto",
+    ],
+)
+
+proto_library_with_info(
+    name = "operations_proto_with_info",
+    deps = [
+        ":operations_proto",
+        "//google/cloud:common_resources_proto",
+    ],
+)
+
+go_gapic_library(
+    name = "compute_go_gapic",
+    srcs = [":compute_proto"],
+    diregapic = True,
+    importpath = "cloud.google.com/go/compute/apiv1;compute",
+    service_yaml = "compute_v1.yaml",
+    transport = "rest",
+)
+
+go_gapic_library(
+    name = "operations_go_gapic",
+    srcs = [":operations_proto_with_info"],
+    diregapic = False,
+    importpath = "cloud.google.com/go/compute/operations/apiv1;operations",
+    service_yaml = "compute_v1.yaml",
+    transport = "grpc+rest",
+)
+`)
+	got, err := ParseAll(buildPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Config{
+		{
+			HasGAPIC:        true,
+			DIREGAPIC:       true,
+			DIREGAPICProtos: []string{"compute.proto"},
+			GAPICImportPath: "cloud.google.com/go/compute/apiv1;compute",
+			ServiceYAML:     "compute_v1.yaml",
+			Transport:       "rest",
+		},
+		{
+			HasGAPIC:        true,
+			GAPICImportPath: "cloud.google.com/go/compute/operations/apiv1;operations",
+			ServiceYAML:     "compute_v1.yaml",
+			Transport:       "grpc+rest",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse_SelectAndConcatenation(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		content string
+		want    *Config
+	}{
+		{
+			name: "select compilers",
+			content: `
+go_proto_library(
+    name = "speech_go_proto",
+    compilers = select({
+        ":legacy": ["@io_bazel_rules_go//proto:go_proto"],
+        "//conditions:default": ["@io_bazel_rules_go//proto:go_grpc"],
+    }),
+)
+`,
+			want: &Config{HasLegacyGRPC: true},
+		},
+		{
+			name: "concatenated compilers",
+			content: `
+go_proto_library(
+    name = "speech_go_proto",
+    compilers = ["@io_bazel_rules_go//proto:go_proto"] + ["@io_bazel_rules_go//proto:go_grpc"],
+)
+`,
+			want: &Config{HasLegacyGRPC: true},
+		},
+		{
+			name: "select and concatenated protos",
+			content: `
+proto_library(
+    name = "compute_proto",
+    srcs = ["compute.proto"] + select({
+        "//conditions:default": ["compute_small.proto"],
+    }),
+)
+
+go_gapic_library(
+    name = "compute_go_gapic",
+    srcs = [":compute_proto"],
+    diregapic = True,
+    importpath = "cloud.google.com/go/compute/apiv1;compute",
+    service_yaml = "compute_v1.yaml",
+)
+`,
+			want: &Config{
+				HasGAPIC:        true,
+				DIREGAPIC:       true,
+				DIREGAPICProtos: []string{"compute.proto", "compute_small.proto"},
+				GAPICImportPath: "cloud.google.com/go/compute/apiv1;compute",
+				ServiceYAML:     "compute_v1.yaml",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := mustParse(t, test.content)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_UnsupportedExpression(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "variable",
+			content: `
+go_proto_library(
+    name = "speech_go_proto",
+    compilers = COMPILERS,
+)
+`,
+		},
+		{
+			name: "select without default",
+			content: `
+go_proto_library(
+    name = "speech_go_proto",
+    compilers = select({
+        ":legacy": ["@io_bazel_rules_go//proto:go_grpc"],
+    }),
+)
+`,
+		},
+		{
+			name: "function call",
+			content: `
+go_proto_library(
+    name = "speech_go_proto",
+    compilers = glob(["*.proto"]),
+)
+`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(writeBuild(t, test.content))
+			if !errors.Is(err, errUnsupportedExpr) {
+				t.Errorf("want error %v, got %v", errUnsupportedExpr, err)
+			}
+		})
+	}
+}
+
+func writeBuild(t *testing.T, content string) string {
+	t.Helper()
+	buildPath := filepath.Join(t.TempDir(), "BUILD.bazel")
+	if err := os.WriteFile(buildPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return buildPath
+}
+
+func TestTransports(t *testing.T) {
+	got, err := Transports(writeBuild(t, `
+go_gapic_library(
+    name = "foo_go_gapic",
+    importpath = "cloud.google.com/go/foo/apiv1;foo",
+    service_yaml = "foo_v1.yaml",
+    transport = "grpc+rest",
+)
+
+py_gapic_library(
+    name = "foo_py_gapic",
+    transport = "grpc",
+)
+
+java_gapic_library(
+    name = "foo_java_gapic",
+)
+
+ruby_cloud_gapic_library(
+    name = "foo_ruby_gapic",
+    transport = "rest",
+)
+
+unknown_gapic_library(
+    name = "foo_unknown_gapic",
+    transport = "grpc",
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"go":     "grpc+rest",
+		"python": "grpc",
+		"ruby":   "rest",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTransports_Error(t *testing.T) {
+	if _, err := Transports(filepath.Join(t.TempDir(), "BUILD.bazel")); err == nil {
+		t.Error("Transports() succeeded; want error")
+	}
+}
+
 func mustParse(t *testing.T, content string) *Config {
 	t.Helper()
 	tmpDir := t.TempDir()
@@ -17,6 +17,7 @@
 package config
 
 //go:generate go run -tags configdocgen ../../cmd/config_doc_generate.go -input . -output ../../doc/config-schema.md
+//go:generate go run -tags configdocgen ../../cmd/config_doc_generate.go -input . -output ../../doc/librarian.schema.json -format jsonschema
 
 // Config represents a librarian.yaml configuration file.
 type Config struct {
@@ -38,12 +39,70 @@ type Config struct {
 	// Release holds the configuration parameter for publishing and release subcommands.
 	Release *Release `yaml:"release,omitempty"`
 
+	// PullRequest configures the pull request opened after librarianops
+	// regenerates the libraries in this repository.
+	PullRequest *PullRequest `yaml:"pull_request,omitempty"`
+
+	// Hooks lists commands to run at points of the generate lifecycle.
+	Hooks *Hooks `yaml:"hooks,omitempty"`
+
 	// Default contains default settings for all libraries. They apply to all libraries unless overridden.
 	Default *Default `yaml:"default,omitempty"`
 
 	// Libraries contains configuration overrides for libraries that need
 	// special handling, and differ from default settings.
 	Libraries []*Library `yaml:"libraries,omitempty"`
+
+	// LibrariesInclude lists glob patterns, relative to the directory of
+	// librarian.yaml, of YAML files that each contain a list of libraries.
+	// The libraries in the matching files are merged into Libraries when the
+	// configuration is loaded, which keeps configurations with many libraries
+	// manageable.
+	LibrariesInclude []string `yaml:"libraries_include,omitempty"`
+}
+
+// PullRequest holds text/template templates for the pull request opened
+// after regenerating libraries.
+type PullRequest struct {
+	// Title is the template for the pull request title. Templates can use
+	// .Timestamp (the time of the run), .Branch, .Libraries (the names of
+	// the libraries with changes), .Version (the librarian version) and .Sources. If empty,
+	// a default title naming the updated sources is used.
+	Title string `yaml:"title,omitempty"`
+
+	// Body is the template for the pull request body, with the same data as
+	// Title. If empty, a default body naming the librarian version and the
+	// updated sources is used.
+	Body string `yaml:"body,omitempty"`
+}
+
+// Hooks lists, for each point of the generate lifecycle, the commands to run
+// there. At each point, every hook is run for each library being generated,
+// in the order the libraries and hooks are listed. The commands run in the
+// directory of librarian.yaml, with the LIBRARIAN_LIBRARY and
+// LIBRARIAN_LIBRARY_DIR environment variables set to the library name and
+// the directory holding its files. A failing hook fails the run.
+type Hooks struct {
+	// PostFormat hooks run after the generated code is formatted. They do
+	// not run when formatting is skipped.
+	PostFormat []*Hook `yaml:"post_format,omitempty"`
+
+	// PostGenerate hooks run after every library has been generated, before
+	// formatting.
+	PostGenerate []*Hook `yaml:"post_generate,omitempty"`
+
+	// PreClean hooks run before the output of a library is cleaned.
+	PreClean []*Hook `yaml:"pre_clean,omitempty"`
+}
+
+// Hook is a named command run at a point of the generate lifecycle.
+type Hook struct {
+	// Name identifies the hook in logs and errors.
+	Name string `yaml:"name"`
+
+	// Command is the program to run followed by its arguments, such as
+	// ["make", "lint"]. It is not interpreted by a shell.
+	Command []string `yaml:"command"`
 }
 
 // Release holds the configuration parameter for publish command.
@@ -64,8 +123,8 @@ type Release struct {
 	// Remote sets the name of the source-of-truth remote for releases, typically `upstream`.
 	Remote string `yaml:"remote,omitempty"`
 
-	// An alternative location for the `roots.pem` file. If empty it has no
-	// effect.
+	// An alternative location for the `roots.pem` file. If set, it must
+	// contain PEM certificates. If empty it has no effect.
 	RootsPem string `yaml:"roots_pem,omitempty"`
 
 	// Tools defines the list of tools to install, indexed by installer.
@@ -122,14 +181,41 @@ type Source struct {
 
 // Default contains default settings for all libraries.
 type Default struct {
+	// CodeOwners maps API path prefixes, such as "google/cloud/speech", to
+	// owners, such as "@googleapis/speech-team". If set, a CODEOWNERS file
+	// assigning each generated library directory to the owners of its APIs
+	// is written to that directory, and the files of all libraries are
+	// concatenated into a CODEOWNERS file at the repository root. The
+	// longest matching prefix wins.
+	CodeOwners map[string]string `yaml:"code_owners,omitempty"`
+
+	// CompatibilityMatrix, if true, writes a COMPATIBILITY.md file to each
+	// library output directory after generation, listing the languages the
+	// API allowlist permits for each of the library's APIs with their
+	// transports, and the library's transport and release level.
+	CompatibilityMatrix bool `yaml:"compatibility_matrix,omitempty"`
+
+	// FileManifest, if true, writes a MANIFEST.files.json file to each
+	// library output directory after generation, listing the generated
+	// files relative to that directory.
+	FileManifest bool `yaml:"file_manifest,omitempty"`
+
 	// Output is the directory where code is written. For example, for Rust
 	// this is src/generated.
 	Output string `yaml:"output,omitempty"`
 
+	// PreservedFiles lists files at the root of each library output
+	// directory that are never removed during regeneration, even if they are
+	// not listed in Library.Keep. If unset, it defaults to .gitattributes,
+	// .gitignore, CODEOWNERS and OWNERS.
+	PreservedFiles []string `yaml:"preserved_files,omitempty"`
+
 	// ReleaseLevel is either "stable" or "preview".
 	ReleaseLevel string `yaml:"release_level,omitempty"`
 
 	// TagFormat is the template for git tags, such as "{name}/v{version}".
+	// The supported placeholders are {name}, {version}, {major}, {minor},
+	// {patch} and {date}, the release date in the form "2006-01-02".
 	TagFormat string `yaml:"tag_format,omitempty"`
 
 	// Transport is the transport protocol, such as "grpc+rest" or "grpc".
@@ -160,7 +246,8 @@ type Library struct {
 	// libraries).
 	APIs []*API `yaml:"apis,omitempty"`
 
-	// CopyrightYear is the copyright year for the library.
+	// CopyrightYear is the copyright year for the library. When empty, the
+	// current year at generation time is used.
 	CopyrightYear string `yaml:"copyright_year,omitempty"`
 
 	// DescriptionOverride overrides the library description.
@@ -177,6 +264,10 @@ type Library struct {
 	// overrides Default.ReleaseLevel.
 	ReleaseLevel string `yaml:"release_level,omitempty"`
 
+	// RequirePublishing skips generation of this library, with a log message,
+	// when the service config of any of its APIs has no publishing section.
+	RequirePublishing bool `yaml:"require_publishing,omitempty"`
+
 	// Roots specifies the source roots to use for generation. Defaults to googleapis.
 	Roots []string `yaml:"roots,omitempty"`
 
@@ -186,7 +277,8 @@ type Library struct {
 	// SkipPublish disables publishing for this library.
 	SkipPublish bool `yaml:"skip_publish,omitempty"`
 
-	// SkipRelease disables releasing for this library.
+	// SkipRelease disables releasing for this library. It is skipped by
+	// bump --all and publish, and bumping it by name is an error.
 	SkipRelease bool `yaml:"skip_release,omitempty"`
 
 	// SpecificationFormat specifies the API specification format. Valid values
@@ -159,3 +159,43 @@ func TestConfigReadAndWrite(t *testing.T) {
 		t.Errorf("mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestGoModuleResolveImportPath(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		module *GoModule
+		base   string
+		want   string
+	}{
+		{
+			name: "nil module",
+			base: "cloud.google.com/go/pubsub",
+			want: "cloud.google.com/go/pubsub",
+		},
+		{
+			name:   "v1",
+			module: &GoModule{},
+			base:   "cloud.google.com/go/pubsub",
+			want:   "cloud.google.com/go/pubsub",
+		},
+		{
+			name:   "v2",
+			module: &GoModule{ModulePathVersion: "v2"},
+			base:   "cloud.google.com/go/pubsub",
+			want:   "cloud.google.com/go/pubsub/v2",
+		},
+		{
+			name:   "nested module",
+			module: &GoModule{ModulePathVersion: "v3"},
+			base:   "cloud.google.com/go/bigquery/storage",
+			want:   "cloud.google.com/go/bigquery/storage/v3",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.module.ResolveImportPath(test.base)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidDartConstraint is returned by ValidateDartPackages when a version
+// constraint does not follow pub's grammar.
+var ErrInvalidDartConstraint = errors.New("invalid Dart version constraint")
+
+// ErrEmptyDartDependency is returned by ValidateDartDependencies when a
+// comma-separated dependency list contains an empty entry, such as "a,,b".
+var ErrEmptyDartDependency = errors.New("empty Dart dependency")
+
+var (
+	pubVersion = `\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`
+	// pubCaret matches a version or a caret constraint, such as "^2.0.0".
+	pubCaret = regexp.MustCompile(`^\^?` + pubVersion + `$`)
+	// pubRange matches a range of one or more comparisons, such as
+	// ">=1.0.0 <2.0.0".
+	pubRange = regexp.MustCompile(`^(?:(?:>=|<=|>|<)\s*` + pubVersion + `\s*)+$`)
+)
+
+// ValidateDartPackages checks that every value of packages, which maps a
+// "package:" key to a version constraint as in DartPackage.Packages, is a
+// constraint pub accepts: "any", a version, a caret constraint, or a range of
+// comparisons. The error lists every invalid entry by key.
+func ValidateDartPackages(packages map[string]string) error {
+	var bad []string
+	for key, constraint := range packages {
+		if !validDartConstraint(constraint) {
+			bad = append(bad, fmt.Sprintf("%s: %q", key, constraint))
+		}
+	}
+	if len(bad) == 0 {
+		return nil
+	}
+	slices.Sort(bad)
+	return fmt.Errorf("%w: %s", ErrInvalidDartConstraint, strings.Join(bad, ", "))
+}
+
+func validDartConstraint(constraint string) bool {
+	constraint = strings.TrimSpace(constraint)
+	return constraint == "any" || pubCaret.MatchString(constraint) || pubRange.MatchString(constraint)
+}
+
+// ValidateDartDependencies checks that the comma-separated list deps, as in
+// DartPackage.Dependencies, has no entries that are empty after trimming
+// whitespace. An empty list is valid.
+func ValidateDartDependencies(deps string) error {
+	if strings.TrimSpace(deps) == "" {
+		return nil
+	}
+	for i, dep := range strings.Split(deps, ",") {
+		if strings.TrimSpace(dep) == "" {
+			return fmt.Errorf("%w at position %d in %q", ErrEmptyDartDependency, i, deps)
+		}
+	}
+	return nil
+}
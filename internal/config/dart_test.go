@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateDartPackages(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		packages map[string]string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "caret",
+			packages: map[string]string{"package:googleapis_auth": "^2.0.0"},
+		},
+		{
+			name:     "range",
+			packages: map[string]string{"package:http": ">=1.1.0 <2.0.0"},
+		},
+		{
+			name: "exact, pre-release and any",
+			packages: map[string]string{
+				"package:protobuf":    "4.0.0",
+				"package:google_api":  "^0.5.0-wip",
+				"package:collection":  "any",
+				"package:meta":        ">= 1.0.0",
+				"package:fixnum":      "<2.0.0",
+				"package:http_parser": ">4.0.0",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if err := ValidateDartPackages(test.packages); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestValidateDartPackages_Error(t *testing.T) {
+	packages := map[string]string{
+		"package:googleapis_auth": "^2.0.0",
+		"package:http":            "~1.0",
+		"package:protobuf":        ">=1.0.0 <",
+	}
+	err := ValidateDartPackages(packages)
+	if !errors.Is(err, ErrInvalidDartConstraint) {
+		t.Fatalf("want error %v, got %v", ErrInvalidDartConstraint, err)
+	}
+	for _, want := range []string{`package:http: "~1.0"`, `package:protobuf: ">=1.0.0 <"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "googleapis_auth") {
+		t.Errorf("error %q lists a valid entry", err)
+	}
+}
+
+func TestValidateDartDependencies(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		deps    string
+		wantErr error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "whitespace only",
+			deps: "  ",
+		},
+		{
+			name: "trimmed entries",
+			deps: "a, b ,c",
+		},
+		{
+			name:    "embedded empty",
+			deps:    "a,,b",
+			wantErr: ErrEmptyDartDependency,
+		},
+		{
+			name:    "embedded whitespace",
+			deps:    "a, ,b",
+			wantErr: ErrEmptyDartDependency,
+		},
+		{
+			name:    "trailing comma",
+			deps:    "a,b,",
+			wantErr: ErrEmptyDartDependency,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDartDependencies(test.deps)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+// ErrDuplicateLibrary is returned by IncludeLibraries when an included file
+// declares a library whose name is already in use.
+var ErrDuplicateLibrary = errors.New("duplicate library name")
+
+// Read reads the librarian.yaml at path and appends the libraries from the
+// files matching LibrariesInclude, relative to the directory of path.
+func Read(path string) (*Config, error) {
+	c, err := yaml.Read[Config](path)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.IncludeLibraries(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// IncludeLibraries appends the libraries from the files matching
+// LibrariesInclude to Libraries. Patterns are relative to dir, which is
+// normally the directory of librarian.yaml, and the matching files are read
+// in lexical order.
+func (c *Config) IncludeLibraries(dir string) error {
+	glob := func(pattern string) ([]string, error) {
+		return filepath.Glob(filepath.Join(dir, pattern))
+	}
+	return c.IncludeLibrariesFunc(glob, os.ReadFile)
+}
+
+// IncludeLibrariesFunc is like IncludeLibraries, but finds the files
+// matching a pattern with glob and reads them with readFile, so that they
+// can come from somewhere other than the working tree, such as a git
+// revision.
+func (c *Config) IncludeLibrariesFunc(glob func(pattern string) ([]string, error), readFile func(name string) ([]byte, error)) error {
+	files, err := includedFiles(glob, c.LibrariesInclude)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool)
+	for _, lib := range c.Libraries {
+		names[lib.Name] = true
+	}
+	for _, file := range files {
+		data, err := readFile(file)
+		if err != nil {
+			return err
+		}
+		libs, err := yaml.Unmarshal[[]*Library](data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		for _, lib := range *libs {
+			if names[lib.Name] {
+				return fmt.Errorf("%w %q in %s", ErrDuplicateLibrary, lib.Name, file)
+			}
+			names[lib.Name] = true
+			c.Libraries = append(c.Libraries, lib)
+		}
+	}
+	return nil
+}
+
+// Write writes c to the librarian.yaml at path. A library that was merged
+// from a file matching LibrariesInclude is written back to that file rather
+// than to librarian.yaml; every other library, including ones added since the
+// configuration was loaded, is written to librarian.yaml.
+func Write(path string, c *Config) error {
+	if len(c.LibrariesInclude) == 0 {
+		return yaml.Write(path, c)
+	}
+	dir := filepath.Dir(path)
+	files, err := includedFiles(func(pattern string) ([]string, error) {
+		return filepath.Glob(filepath.Join(dir, pattern))
+	}, c.LibrariesInclude)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*Library)
+	for _, lib := range c.Libraries {
+		byName[lib.Name] = lib
+	}
+	included := make(map[string]bool)
+	for _, file := range files {
+		libs, err := yaml.Read[[]*Library](file)
+		if err != nil {
+			return err
+		}
+		updated := []*Library{}
+		for _, lib := range *libs {
+			if current, ok := byName[lib.Name]; ok && !included[lib.Name] {
+				updated = append(updated, current)
+				included[lib.Name] = true
+			}
+		}
+		if err := yaml.Write(file, updated); err != nil {
+			return err
+		}
+	}
+	root := *c
+	root.Libraries = slices.DeleteFunc(slices.Clone(c.Libraries), func(lib *Library) bool {
+		return included[lib.Name]
+	})
+	return yaml.Write(path, &root)
+}
+
+func includedFiles(glob func(pattern string) ([]string, error), patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("libraries_include %q: %w", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+func writeIncludeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIncludeLibraries(t *testing.T) {
+	dir := t.TempDir()
+	writeIncludeFiles(t, dir, map[string]string{
+		"libs/storage.yaml": "- name: google-cloud-storage\n  version: 1.0.0\n",
+		"libs/pubsub.yaml":  "- name: google-cloud-pubsub\n  version: 2.0.0\n- name: google-cloud-pubsublite\n",
+		"libs/README.md":    "not a library file\n",
+	})
+	cfg := &Config{
+		Libraries:        []*Library{{Name: "google-cloud-bigquery"}},
+		LibrariesInclude: []string{"libs/*.yaml"},
+	}
+	if err := cfg.IncludeLibraries(dir); err != nil {
+		t.Fatal(err)
+	}
+	want := []*Library{
+		{Name: "google-cloud-bigquery"},
+		{Name: "google-cloud-pubsub", Version: "2.0.0"},
+		{Name: "google-cloud-pubsublite"},
+		{Name: "google-cloud-storage", Version: "1.0.0"},
+	}
+	if diff := cmp.Diff(want, cfg.Libraries); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRead_LibrariesInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeIncludeFiles(t, dir, map[string]string{
+		"librarian.yaml":    "language: go\nlibraries:\n  - name: bigquery\nlibraries_include:\n  - libs/*.yaml\n",
+		"libs/storage.yaml": "- name: storage\n  version: 1.0.0\n",
+	})
+	cfg, err := Read(filepath.Join(dir, "librarian.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Library{
+		{Name: "bigquery"},
+		{Name: "storage", Version: "1.0.0"},
+	}
+	if diff := cmp.Diff(want, cfg.Libraries); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIncludeLibraries_Error(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		files     map[string]string
+		libraries []*Library
+		include   []string
+		wantErr   error
+	}{
+		{
+			name: "duplicate across files",
+			files: map[string]string{
+				"libs/a.yaml": "- name: google-cloud-storage\n",
+				"libs/b.yaml": "- name: google-cloud-storage\n",
+			},
+			include: []string{"libs/*.yaml"},
+			wantErr: ErrDuplicateLibrary,
+		},
+		{
+			name: "duplicate of librarian.yaml",
+			files: map[string]string{
+				"libs/a.yaml": "- name: google-cloud-storage\n",
+			},
+			libraries: []*Library{{Name: "google-cloud-storage"}},
+			include:   []string{"libs/*.yaml"},
+			wantErr:   ErrDuplicateLibrary,
+		},
+		{
+			name:    "bad pattern",
+			include: []string{"libs/[.yaml"},
+			wantErr: filepath.ErrBadPattern,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeIncludeFiles(t, dir, test.files)
+			cfg := &Config{Libraries: test.libraries, LibrariesInclude: test.include}
+			if err := cfg.IncludeLibraries(dir); !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWrite_LibrariesInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeIncludeFiles(t, dir, map[string]string{
+		"libs/a.yaml": "- name: google-cloud-pubsub\n  version: 2.0.0\n- name: google-cloud-removed\n",
+		"libs/b.yaml": "- name: google-cloud-storage\n  version: 1.0.0\n",
+	})
+	path := filepath.Join(dir, "librarian.yaml")
+	cfg := &Config{
+		Language:         "go",
+		LibrariesInclude: []string{"libs/*.yaml"},
+		Libraries: []*Library{
+			{Name: "google-cloud-bigquery"},
+			{Name: "google-cloud-pubsub", Version: "2.1.0"},
+			{Name: "google-cloud-storage", Version: "1.0.0"},
+		},
+	}
+	if err := Write(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		file string
+		want []*Library
+	}{
+		{"libs/a.yaml", []*Library{{Name: "google-cloud-pubsub", Version: "2.1.0"}}},
+		{"libs/b.yaml", []*Library{{Name: "google-cloud-storage", Version: "1.0.0"}}},
+	} {
+		got, err := yaml.Read[[]*Library](filepath.Join(dir, test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, *got); diff != "" {
+			t.Errorf("%s mismatch (-want +got):\n%s", test.file, diff)
+		}
+	}
+	got, err := yaml.Read[Config](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Config{
+		Language:         "go",
+		LibrariesInclude: []string{"libs/*.yaml"},
+		Libraries:        []*Library{{Name: "google-cloud-bigquery"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("librarian.yaml mismatch (-want +got):\n%s", diff)
+	}
+	// The caller's configuration is not modified.
+	if len(cfg.Libraries) != 3 {
+		t.Errorf("Write modified the libraries of the configuration: %v", cfg.Libraries)
+	}
+}
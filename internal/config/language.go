@@ -19,10 +19,22 @@ import "github.com/googleapis/librarian/internal/yaml"
 // GoModule represents the Go-specific configuration for a library.
 type GoModule struct {
 	DeleteGenerationOutputPaths []string `yaml:"delete_generation_output_paths,omitempty"`
+	DisableGoimports            bool     `yaml:"disable_goimports,omitempty"`
 	GoAPIs                      []*GoAPI `yaml:"go_apis,omitempty"`
 	ModulePathVersion           string   `yaml:"module_path_version,omitempty"`
 }
 
+// ResolveImportPath returns the import path of the module rooted at base,
+// such as "cloud.google.com/go/pubsub". If ModulePathVersion is set, as it is
+// for modules at v2+, it is appended as the major version suffix, e.g.
+// "cloud.google.com/go/pubsub/v2". A nil GoModule resolves to base.
+func (m *GoModule) ResolveImportPath(base string) string {
+	if m == nil || m.ModulePathVersion == "" {
+		return base
+	}
+	return base + "/" + m.ModulePathVersion
+}
+
 // GoAPI represents configuration for a single API api within a Go module.
 type GoAPI struct {
 	Path            string   `yaml:"path,omitempty"`
@@ -40,6 +52,9 @@ type RustDefault struct {
 	// DisabledRustdocWarnings is a list of rustdoc warnings to disable.
 	DisabledRustdocWarnings []string `yaml:"disabled_rustdoc_warnings,omitempty"`
 
+	// DisabledClippyWarnings is a list of clippy warnings to disable.
+	DisabledClippyWarnings []string `yaml:"disabled_clippy_warnings,omitempty"`
+
 	// GenerateSetterSamples indicates whether to generate setter samples.
 	GenerateSetterSamples string `yaml:"generate_setter_samples,omitempty"`
 
@@ -90,6 +105,10 @@ type RustModule struct {
 	// (e.g., "crate::generated::gapic::model").
 	ModulePath string `yaml:"module_path,omitempty"`
 
+	// ModuleRoots overrides where named source roots resolve on disk for this
+	// module. Each key is a source root key (e.g., "googleapis-root" or
+	// "discovery-root") and each value is the directory to use instead of the
+	// library-level source for that root.
 	ModuleRoots map[string]string `yaml:"module_roots,omitempty"`
 
 	// NameOverrides contains codec-level overrides for type and service names.
@@ -162,9 +181,6 @@ type RustCrate struct {
 	// SkippedIds is a list of IDs to skip.
 	SkippedIds []string `yaml:"skipped_ids,omitempty"`
 
-	// DisabledClippyWarnings is a list of clippy warnings to disable.
-	DisabledClippyWarnings []string `yaml:"disabled_clippy_warnings,omitempty"`
-
 	// HasVeneer indicates whether the crate has a veneer.
 	HasVeneer bool `yaml:"has_veneer,omitempty"`
 
@@ -263,7 +279,9 @@ type RustPoller struct {
 // PythonPackage contains Python-specific library configuration.
 type PythonPackage struct {
 	// OptArgs contains additional options passed to the generator, where
-	// the options are common to all apis.
+	// the options are common to all apis. All options are passed to the
+	// generator as a single comma-separated list, so an option must not
+	// contain a comma.
 	// Example: ["warehouse-package-name=google-cloud-batch"]
 	OptArgs []string `yaml:"opt_args,omitempty"`
 
@@ -273,6 +291,18 @@ type PythonPackage struct {
 	// that API.
 	// Example: {"google/cloud/secrets/v1beta": ["python-gapic-name=secretmanager"]}
 	OptArgsByAPI map[string][]string `yaml:"opt_args_by_api,omitempty"`
+
+	// Constraints maps a Python package name to the version it is pinned to.
+	// When set, a constraints.txt file pinning each package is written to the
+	// library output, so that transitive dependencies can be installed
+	// reproducibly.
+	// Example: {"protobuf": "5.29.3", "grpcio": "1.70.0"}
+	Constraints map[string]string `yaml:"constraints,omitempty"`
+
+	// CompileCheck, if true, byte-compiles every generated .py file after
+	// generation, failing if any of them has a syntax error. It requires
+	// python3 on the PATH.
+	CompileCheck bool `yaml:"compile_check,omitempty"`
 }
 
 // DartPackage contains Dart-specific library configuration.
@@ -290,7 +320,8 @@ type DartPackage struct {
 	// ExtraImports is additional imports to include in the generated library.
 	ExtraImports string `yaml:"extra_imports,omitempty"`
 
-	// IncludeList is a list of items to include.
+	// IncludeList is a list of proto files to include, relative to the API
+	// path (e.g., "date.proto"). If empty, all proto files are included.
 	IncludeList []string `yaml:"include_list,omitempty"`
 
 	// IssueTrackerURL is the URL for the issue tracker.
@@ -326,6 +357,11 @@ type DartPackage struct {
 	// RepositoryURL is the URL to the repository for this package.
 	RepositoryURL string `yaml:"repository_url,omitempty"`
 
+	// StrictMerge, if true, logs a warning whenever a library's Packages,
+	// Prefixes or Protos entry overrides a default entry with a different
+	// value. The library value still wins.
+	StrictMerge bool `yaml:"strict_merge,omitempty"`
+
 	// TitleOverride overrides the API title.
 	TitleOverride string `yaml:"title_override,omitempty"`
 
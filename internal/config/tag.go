@@ -0,0 +1,94 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTagFormat is returned when a tag format uses an unknown
+// placeholder, or a version cannot be split into the parts a tag format
+// needs.
+var ErrInvalidTagFormat = errors.New("invalid tag format")
+
+// tagPlaceholder matches a placeholder in a tag format, such as "{version}".
+var tagPlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// tagPlaceholders are the placeholders supported in Default.TagFormat.
+var tagPlaceholders = []string{"date", "major", "minor", "name", "patch", "version"}
+
+// ValidateTagFormat checks that format only uses the placeholders supported
+// by FormatTag.
+func ValidateTagFormat(format string) error {
+	for _, m := range tagPlaceholder.FindAllStringSubmatch(format, -1) {
+		if !slices.Contains(tagPlaceholders, m[1]) {
+			return fmt.Errorf("%w %q: unknown placeholder %s, want one of {%s}",
+				ErrInvalidTagFormat, format, m[0], strings.Join(tagPlaceholders, "}, {"))
+		}
+	}
+	return nil
+}
+
+// FormatTag returns the tag for version of the library called name, replacing
+// these placeholders in format:
+//
+//   - {name}: the library name
+//   - {version}: the full version, such as "1.2.3-preview.1"
+//   - {major}, {minor} and {patch}: the parts of the version core
+//   - {date}: date, in the form "2006-01-02"
+func FormatTag(format, name, version string, date time.Time) (string, error) {
+	return formatTag(format, name, version, date.Format(time.DateOnly))
+}
+
+// TagPattern returns a git glob pattern matching the tags FormatTag returns
+// for version of the library called name on any date.
+func TagPattern(format, name, version string) (string, error) {
+	return formatTag(format, name, version, "[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]")
+}
+
+// formatTag implements FormatTag and TagPattern, with date already formatted.
+func formatTag(format, name, version, date string) (string, error) {
+	if err := ValidateTagFormat(format); err != nil {
+		return "", err
+	}
+	core, _, _ := strings.Cut(version, "+")
+	core, _, _ = strings.Cut(core, "-")
+	parts := strings.Split(core, ".")
+	values := map[string]string{
+		"date":    date,
+		"name":    name,
+		"version": version,
+	}
+	if len(parts) == 3 {
+		values["major"], values["minor"], values["patch"] = parts[0], parts[1], parts[2]
+	}
+	var err error
+	tag := tagPlaceholder.ReplaceAllStringFunc(format, func(placeholder string) string {
+		value, ok := values[strings.Trim(placeholder, "{}")]
+		if !ok && err == nil {
+			err = fmt.Errorf("%w %q: version %q has no %s", ErrInvalidTagFormat, format, version, placeholder)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return tag, nil
+}
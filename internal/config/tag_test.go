@@ -0,0 +1,142 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormatTag(t *testing.T) {
+	date := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name    string
+		format  string
+		version string
+		want    string
+	}{
+		{
+			name:    "name and version",
+			format:  "{name}/v{version}",
+			version: "1.2.3",
+			want:    "google-cloud-foo/v1.2.3",
+		},
+		{
+			name:    "version parts",
+			format:  "{name}-{major}.{minor}.{patch}",
+			version: "1.2.3",
+			want:    "google-cloud-foo-1.2.3",
+		},
+		{
+			name:    "major of a prerelease",
+			format:  "{name}/v{major}/v{version}",
+			version: "2.0.0-preview.1",
+			want:    "google-cloud-foo/v2/v2.0.0-preview.1",
+		},
+		{
+			name:    "patch with build metadata",
+			format:  "p{patch}",
+			version: "1.2.3+build.5",
+			want:    "p3",
+		},
+		{
+			name:    "date",
+			format:  "{name}-{date}",
+			version: "1.2.3",
+			want:    "google-cloud-foo-2026-03-01",
+		},
+		{
+			name:    "no placeholders",
+			format:  "release",
+			version: "1.2.3",
+			want:    "release",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := FormatTag(test.format, "google-cloud-foo", test.version, date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTagPattern(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "without date",
+			format: "{name}/v{version}",
+			want:   "google-cloud-foo/v1.2.3",
+		},
+		{
+			name:   "date",
+			format: "{name}-v{version}-{date}",
+			want:   "google-cloud-foo-v1.2.3-[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := TagPattern(test.format, "google-cloud-foo", "1.2.3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTagPattern_Error(t *testing.T) {
+	_, err := TagPattern("{name}/v{ver}", "google-cloud-foo", "1.2.3")
+	if !errors.Is(err, ErrInvalidTagFormat) {
+		t.Errorf("want error %v, got %v", ErrInvalidTagFormat, err)
+	}
+}
+
+func TestFormatTag_Error(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		format  string
+		version string
+	}{
+		{
+			name:    "unknown placeholder",
+			format:  "{name}/v{ver}",
+			version: "1.2.3",
+		},
+		{
+			name:    "version without parts",
+			format:  "{name}/v{major}",
+			version: "1.2",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := FormatTag(test.format, "google-cloud-foo", test.version, time.Now())
+			if !errors.Is(err, ErrInvalidTagFormat) {
+				t.Errorf("want error %v, got %v", ErrInvalidTagFormat, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Transport values accepted in Default.Transport and Library.Transport.
+const (
+	TransportGRPC     = "grpc"
+	TransportREST     = "rest"
+	TransportGRPCREST = "grpc+rest"
+)
+
+var transports = []string{TransportGRPC, TransportREST, TransportGRPCREST}
+
+// ErrInvalidTransport is returned by Validate when a transport is not one of
+// the known values.
+var ErrInvalidTransport = errors.New("invalid transport")
+
+// ErrInvalidHook is returned by Validate when a hook has no command.
+var ErrInvalidHook = errors.New("invalid hook")
+
+// Validate checks that the values in the configuration are ones librarian
+// understands. An empty transport means the default and is always valid, as
+// is an empty tag format.
+func (c *Config) Validate() error {
+	if err := c.Hooks.validate(); err != nil {
+		return err
+	}
+	if c.Default != nil {
+		if err := validateTransport(c.Default.Transport); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+		if err := ValidateTagFormat(c.Default.TagFormat); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	for _, lib := range c.Libraries {
+		if err := validateTransport(lib.Transport); err != nil {
+			return fmt.Errorf("library %q: %w", lib.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateTransport(transport string) error {
+	if transport == "" || slices.Contains(transports, transport) {
+		return nil
+	}
+	return fmt.Errorf("%w %q, want one of %s", ErrInvalidTransport, transport, strings.Join(transports, ", "))
+}
+
+func (h *Hooks) validate() error {
+	if h == nil {
+		return nil
+	}
+	for point, hooks := range map[string][]*Hook{
+		"post_format":   h.PostFormat,
+		"post_generate": h.PostGenerate,
+		"pre_clean":     h.PreClean,
+	} {
+		for i, hook := range hooks {
+			if len(hook.Command) == 0 {
+				return fmt.Errorf("%w: hooks.%s[%d] %q has no command", ErrInvalidHook, point, i, hook.Name)
+			}
+		}
+	}
+	return nil
+}
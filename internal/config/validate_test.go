@@ -0,0 +1,131 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		name             string
+		defaultTransport string
+		libraryTransport string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:             "grpc",
+			defaultTransport: TransportGRPC,
+			libraryTransport: TransportGRPC,
+		},
+		{
+			name:             "rest",
+			defaultTransport: TransportREST,
+			libraryTransport: TransportREST,
+		},
+		{
+			name:             "grpc+rest",
+			defaultTransport: TransportGRPCREST,
+			libraryTransport: TransportGRPCREST,
+		},
+		{
+			name:             "library overrides default",
+			defaultTransport: TransportGRPCREST,
+			libraryTransport: TransportGRPC,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{
+				Default:   &Default{Transport: test.defaultTransport},
+				Libraries: []*Library{{Name: "secretmanager", Transport: test.libraryTransport}},
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestValidate_NilDefault(t *testing.T) {
+	cfg := &Config{Libraries: []*Library{{Name: "secretmanager"}}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate_Error(t *testing.T) {
+	for _, test := range []struct {
+		name             string
+		defaultTransport string
+		libraryTransport string
+		wantMsg          string
+	}{
+		{
+			name:             "invalid default",
+			defaultTransport: "grcp+rest",
+			wantMsg:          `default: invalid transport "grcp+rest"`,
+		},
+		{
+			name:             "invalid library",
+			libraryTransport: "http",
+			wantMsg:          `library "secretmanager": invalid transport "http"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{
+				Default:   &Default{Transport: test.defaultTransport},
+				Libraries: []*Library{{Name: "secretmanager", Transport: test.libraryTransport}},
+			}
+			err := cfg.Validate()
+			if !errors.Is(err, ErrInvalidTransport) {
+				t.Fatalf("want error %v, got %v", ErrInvalidTransport, err)
+			}
+			if !strings.Contains(err.Error(), test.wantMsg) {
+				t.Errorf("error %q does not contain %q", err, test.wantMsg)
+			}
+		})
+	}
+}
+
+func TestValidate_Hooks(t *testing.T) {
+	cfg := &Config{
+		Hooks: &Hooks{
+			PreClean:     []*Hook{{Name: "backup", Command: []string{"cp", "-r", "src", "backup"}}},
+			PostGenerate: []*Hook{{Name: "empty"}},
+		},
+	}
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalidHook) {
+		t.Fatalf("want error %v, got %v", ErrInvalidHook, err)
+	}
+	if want := `hooks.post_generate[0] "empty"`; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+}
+
+func TestValidate_TagFormat(t *testing.T) {
+	cfg := &Config{Default: &Default{TagFormat: "{name}/v{major}/{build}"}}
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalidTagFormat) {
+		t.Fatalf("want error %v, got %v", ErrInvalidTagFormat, err)
+	}
+	if want := "unknown placeholder {build}"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+}
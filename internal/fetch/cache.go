@@ -62,7 +62,7 @@ const envLibrarianCache = "LIBRARIAN_CACHE"
 //  3. Download tarball, compute SHA256, verify it matches expectedSHA256 from
 //     librarian.yaml, extract, and return the path.
 func RepoDir(ctx context.Context, repo, commit, expectedSHA256 string) (string, error) {
-	cacheDir, err := cacheDir()
+	cacheDir, err := CacheDir()
 	if err != nil {
 		return "", err
 	}
@@ -112,10 +112,10 @@ func RepoDir(ctx context.Context, repo, commit, expectedSHA256 string) (string,
 	return outDir, nil
 }
 
-// cacheDir returns the root cache directory for librarian operations. It
+// CacheDir returns the root cache directory for librarian operations. It
 // checks the $LIBRARIAN_CACHE environment variable, falling back to
 // $HOME/.cache/librarian if not set.
-func cacheDir() (string, error) {
+func CacheDir() (string, error) {
 	if cache := os.Getenv(envLibrarianCache); cache != "" {
 		return cache, nil
 	}
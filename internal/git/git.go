@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"slices"
 	"strings"
 
@@ -120,6 +121,50 @@ func ShowFileAtRevision(ctx context.Context, gitExe, revision, path string) (str
 	return strings.TrimSuffix(output, "\n"), nil
 }
 
+// RepoRelativePath returns path, which may be absolute or relative to the
+// current directory, as a slash-separated path relative to the root of the
+// repository containing the current directory. Such paths are what
+// [ShowFileAtRevision] and [ListFilesAtRevision] expect.
+func RepoRelativePath(ctx context.Context, gitExe, path string) (string, error) {
+	output, err := command.Output(ctx, gitExe, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("failed to find repository root: %w", err)
+	}
+	root, err := filepath.EvalSymlinks(strings.TrimSuffix(output, "\n"))
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	// Resolve symbolic links in the directory, as git reports the root with
+	// them resolved. The file itself may not exist in the working tree.
+	dir, err := filepath.EvalSymlinks(filepath.Dir(abs))
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, filepath.Join(dir, filepath.Base(abs)))
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the repository at %s", path, root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// ListFilesAtRevision returns the paths of the files under dir at the given
+// revision. Both dir and the returned paths are relative to the root of the
+// repository.
+func ListFilesAtRevision(ctx context.Context, gitExe, revision, dir string) ([]string, error) {
+	output, err := command.Output(ctx, gitExe, "ls-tree", "-r", "--name-only", "--full-tree", revision, "--", dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s at %s: %w", dir, revision, err)
+	}
+	return strings.Fields(output), nil
+}
+
 // MatchesBranchPoint returns an error if the local repository has unpushed changes.
 func MatchesBranchPoint(ctx context.Context, gitExe, remote, branch string) error {
 	remoteBranch := fmt.Sprintf("%s/%s", remote, branch)
@@ -144,6 +189,80 @@ func FindCommitsForPath(ctx context.Context, gitExe, path string) ([]string, err
 	return strings.Fields(output), nil
 }
 
+// HeadCommit returns the full hash of the HEAD commit of the repository in
+// dir.
+func HeadCommit(ctx context.Context, gitExe, dir string) (string, error) {
+	output, err := command.Output(ctx, gitExe, "-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD commit of %s: %w", dir, err)
+	}
+	return strings.TrimSuffix(output, "\n"), nil
+}
+
+// CurrentBranch returns the name of the branch checked out in the current
+// repository, or "HEAD" if the head is detached.
+func CurrentBranch(ctx context.Context, gitExe string) (string, error) {
+	output, err := command.Output(ctx, gitExe, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	return strings.TrimSuffix(output, "\n"), nil
+}
+
+// FindCommitsForPathsSince returns the full hashes of the commits after since,
+// up to and including HEAD, that affect any of the given paths in the
+// repository in dir. The commits are returned in normal log order, i.e.
+// latest commit first.
+func FindCommitsForPathsSince(ctx context.Context, gitExe, dir, since string, paths []string) ([]string, error) {
+	args := []string{"-C", dir, "log", "--pretty=format:%H", since + "..HEAD", "--"}
+	args = append(args, paths...)
+	output, err := command.Output(ctx, gitExe, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change commits since %s from paths %v: %w", since, paths, err)
+	}
+	return strings.Fields(output), nil
+}
+
+// CommitMessagesForPathSince returns the full messages of the commits after
+// since, up to and including HEAD, that affect the given path. If since is
+// empty, all commits up to and including HEAD are considered. Commits whose
+// changes within path all match ignoredChanges, as in FilesChangedSince, are
+// skipped. The messages are returned in normal log order, i.e. latest commit
+// first.
+func CommitMessagesForPathSince(ctx context.Context, gitExe, since, path string, ignoredChanges []string) ([]string, error) {
+	revision := "HEAD"
+	if since != "" {
+		revision = since + "..HEAD"
+	}
+	// Each commit is written as NUL, message, SOH and then, with
+	// --name-only, the files it changed within path.
+	output, err := command.Output(ctx, gitExe, "log", "--format=%x00%B%x01", "--name-only", revision, "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit messages since %s from path %s: %w", since, path, err)
+	}
+	var messages []string
+	for _, commit := range strings.Split(output, "\x00") {
+		message, files, _ := strings.Cut(commit, "\x01")
+		if len(ignoredChanges) > 0 && len(filesFilter(ignoredChanges, strings.Split(files, "\n"))) == 0 {
+			continue
+		}
+		if message = strings.TrimSpace(message); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// ListTags returns the tags in the local repository that match the glob
+// pattern, sorted by name.
+func ListTags(ctx context.Context, gitExe, pattern string) ([]string, error) {
+	output, err := command.Output(ctx, gitExe, "tag", "--list", pattern)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(output), nil
+}
+
 // Checkout checks out the given revision. If revision is a commit rather than a
 // branch, this will leave the repository with a detached head. If revision is the
 // name of a valid path, that file is checked out instead. (Git does not provide a
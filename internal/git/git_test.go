@@ -19,12 +19,14 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sample"
 	"github.com/googleapis/librarian/internal/testhelper"
 )
 
@@ -375,6 +377,83 @@ func TestShowFileAtRevision_Error(t *testing.T) {
 	}
 }
 
+func TestRepoRelativePath(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.Setup(t, testhelper.SetupOptions{})
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name string
+		dir  string
+		path string
+		want string
+	}{
+		{
+			name: "relative at root",
+			path: "librarian.yaml",
+			want: "librarian.yaml",
+		},
+		{
+			name: "relative in subdirectory",
+			dir:  sample.Lib1Output,
+			path: "Cargo.toml",
+			want: path.Join(sample.Lib1Output, "Cargo.toml"),
+		},
+		{
+			name: "absolute",
+			dir:  sample.Lib1Output,
+			path: filepath.Join(root, "librarian.yaml"),
+			want: "librarian.yaml",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Chdir(filepath.Join(root, test.dir))
+			got, err := RepoRelativePath(t.Context(), "git", test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("RepoRelativePath() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRepoRelativePath_Error(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.SetupRepo(t)
+	if _, err := RepoRelativePath(t.Context(), "git", filepath.Join(t.TempDir(), "librarian.yaml")); err == nil {
+		t.Error("expected an error for a path outside the repository")
+	}
+}
+
+func TestListFilesAtRevision(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.Setup(t, testhelper.SetupOptions{})
+	got, err := ListFilesAtRevision(t.Context(), "git", "HEAD", sample.Lib1Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		path.Join(sample.Lib1Output, ".repo-metadata.json"),
+		path.Join(sample.Lib1Output, "Cargo.toml"),
+		path.Join(sample.Lib1Output, "src", "lib.rs"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestListFilesAtRevision_Error(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.SetupRepo(t)
+	if _, err := ListFilesAtRevision(t.Context(), "git", "not-a-revision", "."); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
+
 func TestCheckVersion(t *testing.T) {
 	t.Parallel()
 	testhelper.RequireCommand(t, "git")
@@ -460,6 +539,227 @@ func TestFindCommitsForPath_Error(t *testing.T) {
 	}
 }
 
+func TestHeadCommit(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.SetupRepo(t)
+	got, err := HeadCommit(t.Context(), "git", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := command.Output(t.Context(), "git", "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(strings.TrimSpace(want), got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCurrentBranch(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.SetupRepo(t)
+	got, err := CurrentBranch(t.Context(), "git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("main", got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFindCommitsForPathsSince(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	opts := testhelper.SetupOptions{
+		WithChanges: []string{testhelper.ReadmeFile},
+	}
+	testhelper.Setup(t, opts)
+	for _, test := range []struct {
+		name       string
+		since      string
+		paths      []string
+		wantLength int
+	}{
+		{
+			name:       "changed path",
+			since:      "HEAD~",
+			paths:      []string{testhelper.ReadmeFile},
+			wantLength: 1,
+		},
+		{
+			name:       "changed and unchanged paths",
+			since:      "HEAD~",
+			paths:      []string{"this/path/does/not/exist", testhelper.ReadmeFile},
+			wantLength: 1,
+		},
+		{
+			name:  "unchanged path",
+			since: "HEAD~",
+			paths: []string{"this/path/does/not/exist"},
+		},
+		{
+			name:  "since head",
+			since: "HEAD",
+			paths: []string{testhelper.ReadmeFile},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := FindCommitsForPathsSince(t.Context(), "git", ".", test.since, test.paths)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.wantLength != len(got) {
+				t.Errorf("want %d changes, got %d", test.wantLength, len(got))
+			}
+		})
+	}
+}
+
+func TestFindCommitsForPathsSince_SharedPrefix(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.SetupRepo(t)
+	since, err := HeadCommit(t.Context(), "git", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, path := range []string{
+		"google/cloud/foo/v1/foo.proto",
+		"google/cloud/foobar/v1/foobar.proto",
+		"google/cloud/foo/v1beta/foo.proto",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(path), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "add", path); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "commit", "-m", "change "+path); err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(path, "google/cloud/foo/v1/") {
+			head, err := HeadCommit(t.Context(), "git", ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want = append(want, head)
+		}
+	}
+	got, err := FindCommitsForPathsSince(t.Context(), "git", ".", since, []string{"google/cloud/foo/v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFindCommitsForPathsSince_Error(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.SetupRepo(t)
+	if _, err := FindCommitsForPathsSince(t.Context(), "git", ".", "not-a-commit", []string{testhelper.ReadmeFile}); err == nil {
+		t.Errorf("expected an error for an unknown commit, but did not get one")
+	}
+}
+
+func TestCommitMessagesForPathSince(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	opts := testhelper.SetupOptions{
+		WithChanges: []string{testhelper.ReadmeFile},
+	}
+	testhelper.Setup(t, opts)
+	for _, test := range []struct {
+		name           string
+		since          string
+		path           string
+		ignoredChanges []string
+		want           []string
+	}{
+		{
+			name:  "changed path",
+			since: "HEAD~",
+			path:  testhelper.ReadmeFile,
+			want:  []string{"feat: changed file(s)"},
+		},
+		{
+			name:           "only ignored changes",
+			since:          "HEAD~",
+			path:           ".",
+			ignoredChanges: []string{"*.md"},
+		},
+		{
+			name:           "ignored changes do not match",
+			since:          "HEAD~",
+			path:           ".",
+			ignoredChanges: []string{"testdata/**"},
+			want:           []string{"feat: changed file(s)"},
+		},
+		{
+			name:  "unchanged path",
+			since: "HEAD~",
+			path:  "this/path/does/not/exist",
+		},
+		{
+			name:  "since head",
+			since: "HEAD",
+			path:  testhelper.ReadmeFile,
+		},
+		{
+			name: "all history",
+			path: testhelper.ReadmeFile,
+			want: []string{"feat: changed file(s)", "initial version"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := CommitMessagesForPathSince(t.Context(), "git", test.since, test.path, test.ignoredChanges)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCommitMessagesForPathSince_Error(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.SetupRepo(t)
+	if _, err := CommitMessagesForPathSince(t.Context(), "git", "not-a-commit", testhelper.ReadmeFile, nil); err == nil {
+		t.Errorf("expected an error for an unknown commit, but did not get one")
+	}
+}
+
+func TestListTags(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.Setup(t, testhelper.SetupOptions{Tag: "foo-2026-03-02"})
+	for _, tag := range []string{"foo-2026-03-01", "bar-2026-03-01"} {
+		if err := command.Run(t.Context(), "git", "tag", tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, test := range []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "foo-*", want: []string{"foo-2026-03-01", "foo-2026-03-02"}},
+		{pattern: "bar-2026-03-01", want: []string{"bar-2026-03-01"}},
+		{pattern: "baz-*", want: []string{}},
+	} {
+		t.Run(test.pattern, func(t *testing.T) {
+			got, err := ListTags(t.Context(), "git", test.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestCheckout(t *testing.T) {
 	testhelper.RequireCommand(t, "git")
 	opts := testhelper.SetupOptions{
@@ -34,6 +34,9 @@ const (
 type LibrarianState struct {
 	// The name and tag of the generator image to use. tag is required.
 	Image string `yaml:"image" json:"image"`
+	// The digest, in the form "sha256:<hex>", that pins the generator image.
+	// When set, it is used instead of the tag in Image.
+	ImageDigest string `yaml:"image_digest,omitempty" json:"image_digest,omitempty"`
 	// A list of library configurations.
 	Libraries []*LibraryState `yaml:"libraries" json:"libraries"`
 }
@@ -46,6 +49,9 @@ func (s *LibrarianState) Validate() error {
 	if !isValidImage(s.Image) {
 		return fmt.Errorf("invalid image: %q", s.Image)
 	}
+	if s.ImageDigest != "" && !digestRegex.MatchString(s.ImageDigest) {
+		return fmt.Errorf("invalid image digest: %q", s.ImageDigest)
+	}
 	if len(s.Libraries) == 0 {
 		return fmt.Errorf("libraries cannot be empty")
 	}
@@ -76,6 +82,20 @@ func (s *LibrarianState) ImageRefAndTag() (ref string, tag string) {
 	return parseImage(s.Image)
 }
 
+// ImageReference returns the image reference to run. If ImageDigest is set,
+// the image is pinned by digest, for example "gcr.io/my-image@sha256:...",
+// and the tag in Image is ignored. Otherwise Image is returned unchanged.
+func (s *LibrarianState) ImageReference() string {
+	if s == nil {
+		return ""
+	}
+	if s.ImageDigest == "" {
+		return s.Image
+	}
+	ref, _ := parseImage(s.Image)
+	return ref + "@" + s.ImageDigest
+}
+
 // LibraryByID returns the library with the given ID, or nil if not found.
 func (s *LibrarianState) LibraryByID(id string) *LibraryState {
 	for _, lib := range s.Libraries {
@@ -171,6 +191,7 @@ var (
 	semverRegex    = regexp.MustCompile(`^v?\d+\.\d+\.\d+(?:-([a-zA-Z0-9-]+(?:\.[a-zA-Z0-9-]+)*))?$`)
 	hexRegex       = regexp.MustCompile("^[a-fA-F0-9]+$")
 	tagFormatRegex = regexp.MustCompile(`{[^{}]*}`)
+	digestRegex    = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
 )
 
 // Validate checks that the Library is valid.
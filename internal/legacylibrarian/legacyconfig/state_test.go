@@ -63,6 +63,33 @@ func TestLibrarianState_Validate(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "image is required",
 		},
+		{
+			name: "valid image digest",
+			state: &LibrarianState{
+				Image:       "gcr.io/test/image:v1.2.3",
+				ImageDigest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Libraries: []*LibraryState{
+					{
+						ID:          "a/b",
+						SourceRoots: []string{"src/a", "src/b"},
+						APIs: []*API{
+							{
+								Path: "a/b/v1",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid image digest",
+			state: &LibrarianState{
+				Image:       "gcr.io/test/image:v1.2.3",
+				ImageDigest: "sha256:abc",
+			},
+			wantErr:    true,
+			wantErrMsg: "invalid image digest",
+		},
 		{
 			name: "missing libraries",
 			state: &LibrarianState{
@@ -484,6 +511,42 @@ func TestIsValidImage(t *testing.T) {
 	}
 }
 
+func TestLibrarianState_ImageReference(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	for _, test := range []struct {
+		name  string
+		state *LibrarianState
+		want  string
+	}{
+		{
+			name:  "nil state",
+			state: nil,
+			want:  "",
+		},
+		{
+			name:  "tag only",
+			state: &LibrarianState{Image: "gcr.io/test/image:v1.2.3"},
+			want:  "gcr.io/test/image:v1.2.3",
+		},
+		{
+			name:  "digest only",
+			state: &LibrarianState{Image: "gcr.io/test/image", ImageDigest: digest},
+			want:  "gcr.io/test/image@" + digest,
+		},
+		{
+			name:  "tag and digest",
+			state: &LibrarianState{Image: "localhost:5000/test/image:v1.2.3", ImageDigest: digest},
+			want:  "localhost:5000/test/image@" + digest,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.state.ImageReference(); got != test.want {
+				t.Errorf("ImageReference() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
 func TestLibraryState_LibraryByID(t *testing.T) {
 	for _, test := range []struct {
 		name      string
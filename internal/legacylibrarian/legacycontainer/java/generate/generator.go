@@ -17,11 +17,14 @@ package generate
 import (
 	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/bazel"
@@ -39,6 +42,11 @@ var (
 	protocBuild = protoc.Build
 )
 
+var (
+	errExtractedSizeExceeded = errors.New("librariangen: extracted size exceeds limit")
+	errCorruptSrcjar         = errors.New("librariangen: corrupt srcjar")
+)
+
 // Generate is the main entrypoint for the `generate` command. It orchestrates
 // the entire generation process.
 func Generate(ctx context.Context, cfg *generate.Config) error {
@@ -55,6 +63,10 @@ func Generate(ctx context.Context, cfg *generate.Config) error {
 		return fmt.Errorf("librariangen: failed to generate poms for API %s: %w", libraryID, err)
 	}
 
+	if err := warnUnexpectedOutput(cfg.Context.OutputDir, libraryID, cfg.Request.PreserveRegex); err != nil {
+		return err
+	}
+
 	slog.Debug("librariangen: generate command finished")
 	return nil
 }
@@ -83,7 +95,11 @@ func processAPI(ctx context.Context, cfg *generate.Config, libraryID string, api
 	// Unzip the temp-codegen.srcjar.
 	srcjarPath := filepath.Join(outputConfig.GAPICDir, "temp-codegen.srcjar")
 	srcjarDest := outputConfig.GAPICDir
-	if err := unzip(srcjarPath, srcjarDest); err != nil {
+	maxSize := cfg.Context.MaxExtractedSize
+	if maxSize == 0 {
+		maxSize = generate.DefaultMaxExtractedSize
+	}
+	if err := unzip(srcjarPath, srcjarDest, maxSize); err != nil {
 		return fmt.Errorf("librariangen: failed to unzip %s: %w", srcjarPath, err)
 	}
 
@@ -114,7 +130,7 @@ func invokeProtoc(ctx context.Context, genCtx *generate.Context, api *message.AP
 	if err != nil {
 		return fmt.Errorf("librariangen: failed to parse BUILD.bazel for %s: %w", apiServiceDir, err)
 	}
-	args, err := protocBuild(apiServiceDir, bazelConfig, genCtx.SourceDir, outputConfig)
+	args, err := protocBuild(apiServiceDir, bazelConfig, genCtx.SourceDir, genCtx.CommonResourcesProto, outputConfig)
 	if err != nil {
 		return fmt.Errorf("librariangen: failed to build protoc command for api %q: %w", api.Path, err)
 	}
@@ -187,7 +203,9 @@ func restructureOutput(outputDir, libraryID, version string) error {
 	if err := os.RemoveAll(filepath.Join(protoSrcDir, "com", "google", "cloud", "location")); err != nil {
 		return err
 	}
-	if err := os.Remove(filepath.Join(protoSrcDir, "google", "cloud", "CommonResources.java")); err != nil {
+	// CommonResources.java only exists if the common resources proto was
+	// compiled.
+	if err := os.Remove(filepath.Join(protoSrcDir, "google", "cloud", "CommonResources.java")); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
 
@@ -221,6 +239,73 @@ func restructureOutput(outputDir, libraryID, version string) error {
 	return nil
 }
 
+// warnUnexpectedOutput logs a warning for each top-level entry in outputDir
+// that is not a module produced by restructureOutput or pom.Generate, since a
+// stray entry likely indicates a restructuring bug.
+func warnUnexpectedOutput(outputDir, libraryID string, preserveRegex []string) error {
+	unexpected, err := unexpectedOutputEntries(outputDir, libraryID, preserveRegex)
+	if err != nil {
+		return err
+	}
+	for _, name := range unexpected {
+		slog.Warn("librariangen: unexpected entry in output directory", "path", filepath.Join(outputDir, name))
+	}
+	return nil
+}
+
+// unexpectedOutputEntries returns the names of the top-level entries in
+// outputDir that are neither a known module directory, the samples
+// directory, the parent pom.xml, nor matched by one of preserveRegex.
+func unexpectedOutputEntries(outputDir, libraryID string, preserveRegex []string) ([]string, error) {
+	var preserve []*regexp.Regexp
+	for _, expr := range preserveRegex {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("librariangen: invalid preserve regex %q: %w", expr, err)
+		}
+		preserve = append(preserve, re)
+	}
+	mainModule := fmt.Sprintf("google-cloud-%s", libraryID)
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("librariangen: failed to read dir %s: %w", outputDir, err)
+	}
+	var unexpected []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if isModuleEntry(entry, mainModule) || matchesAny(preserve, name) {
+			continue
+		}
+		unexpected = append(unexpected, name)
+	}
+	return unexpected, nil
+}
+
+// isModuleEntry reports whether entry is one of the top-level files or
+// directories of a restructured library whose main module is mainModule.
+func isModuleEntry(entry os.DirEntry, mainModule string) bool {
+	name := entry.Name()
+	if !entry.IsDir() {
+		return name == "pom.xml"
+	}
+	switch {
+	case name == "samples", name == mainModule, name == mainModule+"-bom":
+		return true
+	case strings.HasPrefix(name, "proto-"+mainModule+"-"), strings.HasPrefix(name, "grpc-"+mainModule+"-"):
+		return true
+	}
+	return false
+}
+
+func matchesAny(res []*regexp.Regexp, name string) bool {
+	for _, re := range res {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
 // copyAndMerge recursively copies the contents of src to dest, merging directories.
 func copyAndMerge(src, dest string) error {
 	entries, err := os.ReadDir(src)
@@ -255,13 +340,19 @@ func cleanupIntermediateFiles(outputConfig *protoc.OutputConfig) error {
 	return os.RemoveAll(filepath.Dir(outputConfig.GAPICDir))
 }
 
-func unzip(src, dest string) error {
+// unzip extracts the zip archive src into dest. It returns an error wrapping
+// errExtractedSizeExceeded if the entries declare, or expand to, more than
+// maxSize bytes in total, and an error wrapping errCorruptSrcjar if an entry
+// is truncated, fails its checksum, or would overwrite an earlier entry.
+func unzip(src, dest string, maxSize int64) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		return err
 	}
 	defer r.Close()
 
+	var total int64
+	extracted := make(map[string]bool)
 	for _, f := range r.File {
 		fpath := filepath.Join(dest, f.Name)
 
@@ -274,6 +365,18 @@ func unzip(src, dest string) error {
 			continue
 		}
 
+		// A repeated entry would leave fewer files on disk than the srcjar
+		// lists.
+		if extracted[fpath] {
+			return fmt.Errorf("%w: duplicate entry %s", errCorruptSrcjar, f.Name)
+		}
+		extracted[fpath] = true
+
+		remaining := maxSize - total
+		if f.UncompressedSize64 > uint64(remaining) {
+			return fmt.Errorf("%w: %s declares %d bytes, %d bytes remaining of %d", errExtractedSizeExceeded, f.Name, f.UncompressedSize64, remaining, maxSize)
+		}
+
 		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
 			return err
 		}
@@ -289,9 +392,21 @@ func unzip(src, dest string) error {
 			return err
 		}
 
-		_, copyErr := io.Copy(outFile, rc)
+		// Read at most one byte past the limit, in case the declared size is
+		// wrong, so that exceeding it can be detected.
+		n, copyErr := io.Copy(outFile, io.LimitReader(rc, remaining+1))
 		rc.Close() // Error on read-only file close is less critical
 		closeErr := outFile.Close()
+		total += n
+		if isCorruptEntryErr(copyErr) {
+			copyErr = fmt.Errorf("%w: %s: %w", errCorruptSrcjar, f.Name, copyErr)
+		}
+		if copyErr == nil && total > maxSize {
+			copyErr = fmt.Errorf("%w: %s expands past %d bytes", errExtractedSizeExceeded, f.Name, maxSize)
+		}
+		if copyErr == nil && uint64(n) != f.UncompressedSize64 {
+			copyErr = fmt.Errorf("%w: %s has %d bytes, want %d", errCorruptSrcjar, f.Name, n, f.UncompressedSize64)
+		}
 
 		if copyErr != nil {
 			return copyErr
@@ -302,3 +417,9 @@ func unzip(src, dest string) error {
 	}
 	return nil
 }
+
+// isCorruptEntryErr reports whether err, returned while reading a zip entry,
+// indicates that the entry is truncated or otherwise corrupt.
+func isCorruptEntryErr(err error) bool {
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, zip.ErrFormat) || errors.Is(err, zip.ErrChecksum)
+}
@@ -16,12 +16,18 @@ package generate
 
 import (
 	"archive/zip"
+	"bytes"
 	"context"
 	"errors"
+	"hash/crc32"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/languagecontainer/generate"
 	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/protoc"
 )
@@ -316,6 +322,21 @@ func TestRestructureOutput(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:        "no common resources",
+			libraryName: "my-library",
+			versions:    []string{"v1"},
+			sourceFiles: map[string]string{
+				"v1/gapic/src/main/java/com/google/foo.java": "",
+				"v1/proto/com/google/bar.proto":              "",
+				"v1/grpc/com/google/bar_grpc.java":           "",
+			},
+			expectedFiles: []string{
+				"google-cloud-my-library/src/main/java/com/google/foo.java",
+				"proto-google-cloud-my-library-v1/src/main/java/com/google/bar.proto",
+				"grpc-google-cloud-my-library-v1/src/main/java/com/google/bar_grpc.java",
+			},
+		},
 		{
 			name:        "multiple versions in one library",
 			libraryName: "my-multi-version-library",
@@ -385,6 +406,103 @@ func TestRestructureOutput(t *testing.T) {
 	}
 }
 
+func TestUnexpectedOutputEntries(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         []string
+		preserveRegex []string
+		want          []string
+	}{
+		{
+			name: "only modules",
+			files: []string{
+				"pom.xml",
+				"google-cloud-foo/pom.xml",
+				"google-cloud-foo-bom/pom.xml",
+				"proto-google-cloud-foo-v1/pom.xml",
+				"grpc-google-cloud-foo-v1/pom.xml",
+				"samples/snippets/Foo.java",
+			},
+		},
+		{
+			name: "stray entries",
+			files: []string{
+				"pom.xml",
+				"google-cloud-foo/pom.xml",
+				"v1/gapic/Foo.java",
+				"temp-codegen.srcjar",
+				"proto-google-cloud-bar-v1/pom.xml",
+			},
+			want: []string{"proto-google-cloud-bar-v1", "temp-codegen.srcjar", "v1"},
+		},
+		{
+			name: "preserved entries",
+			files: []string{
+				"pom.xml",
+				"README.md",
+				"handwritten/Foo.java",
+			},
+			preserveRegex: []string{"^README\\.md$", "^handwritten$"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			for _, path := range test.files {
+				fullPath := filepath.Join(e.outputDir, path)
+				if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(fullPath, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := unexpectedOutputEntries(e.outputDir, "foo", test.preserveRegex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWarnUnexpectedOutput(t *testing.T) {
+	e := newTestEnv(t)
+	for _, path := range []string{"pom.xml", "google-cloud-foo/pom.xml", "stray.txt"} {
+		fullPath := filepath.Join(e.outputDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	if err := warnUnexpectedOutput(e.outputDir, "foo", nil); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if !strings.Contains(got, "level=WARN") || !strings.Contains(got, "stray.txt") {
+		t.Errorf("want warning about stray.txt, got %q", got)
+	}
+	if strings.Contains(got, "google-cloud-foo") || strings.Contains(got, "pom.xml") {
+		t.Errorf("want no warning about module entries, got %q", got)
+	}
+}
+
+func TestUnexpectedOutputEntries_InvalidRegex(t *testing.T) {
+	e := newTestEnv(t)
+	if _, err := unexpectedOutputEntries(e.outputDir, "foo", []string{"("}); err == nil {
+		t.Error("unexpectedOutputEntries() succeeded, want error")
+	}
+}
+
 func TestCopyAndMerge(t *testing.T) {
 	e := newTestEnv(t)
 	defer e.cleanup(t)
@@ -459,7 +577,7 @@ func TestUnzip(t *testing.T) {
 
 		// Unzip the file.
 		destDir := filepath.Join(e.outputDir, "unzip-dest")
-		if err := unzip(zipPath, destDir); err != nil {
+		if err := unzip(zipPath, destDir, generate.DefaultMaxExtractedSize); err != nil {
 			t.Fatalf("unzip() failed: %v", err)
 		}
 
@@ -476,7 +594,7 @@ func TestUnzip(t *testing.T) {
 		if err := os.WriteFile(invalidZipPath, []byte("not a zip file"), 0644); err != nil {
 			t.Fatalf("failed to write invalid zip file: %v", err)
 		}
-		if err := unzip(invalidZipPath, e.outputDir); err == nil {
+		if err := unzip(invalidZipPath, e.outputDir, generate.DefaultMaxExtractedSize); err == nil {
 			t.Error("unzip() with invalid zip file should return an error")
 		}
 	})
@@ -512,7 +630,7 @@ func TestUnzip(t *testing.T) {
 			t.Fatalf("failed to chmod read-only dir: %v", err)
 		}
 
-		if err := unzip(validZipPath, readOnlyDir); err == nil {
+		if err := unzip(validZipPath, readOnlyDir, generate.DefaultMaxExtractedSize); err == nil {
 			t.Error("unzip() with read-only destination should return an error")
 		}
 	})
@@ -538,7 +656,7 @@ func TestUnzip(t *testing.T) {
 			t.Fatalf("failed to create unzip dest dir: %v", err)
 		}
 
-		if err := unzip(maliciousZipPath, destDir); err == nil {
+		if err := unzip(maliciousZipPath, destDir, generate.DefaultMaxExtractedSize); err == nil {
 			t.Error("unzip() with malicious zip file should return an error")
 		}
 
@@ -548,6 +666,100 @@ func TestUnzip(t *testing.T) {
 			t.Errorf("malicious file was created at %s", pwnedFile)
 		}
 	})
+
+	t.Run("exceeds max extracted size", func(t *testing.T) {
+		e := newTestEnv(t)
+		defer e.cleanup(t)
+		zipPath := filepath.Join(e.outputDir, "large.zip")
+		f, err := os.Create(zipPath)
+		if err != nil {
+			t.Fatalf("failed to create zip file: %v", err)
+		}
+		defer f.Close()
+		zipWriter := zip.NewWriter(f)
+		for _, name := range []string{"a.txt", "b.txt"} {
+			w, err := zipWriter.Create(name)
+			if err != nil {
+				t.Fatalf("failed to create file in zip: %v", err)
+			}
+			// Highly compressible content expands well past its archived size.
+			if _, err := w.Write(bytes.Repeat([]byte("a"), 1024)); err != nil {
+				t.Fatalf("failed to write file in zip: %v", err)
+			}
+		}
+		if err := zipWriter.Close(); err != nil {
+			t.Fatalf("failed to close zip writer: %v", err)
+		}
+
+		destDir := filepath.Join(e.outputDir, "unzip-dest")
+		err = unzip(zipPath, destDir, 1500)
+		if !errors.Is(err, errExtractedSizeExceeded) {
+			t.Fatalf("unzip() error = %v, want %v", err, errExtractedSizeExceeded)
+		}
+		if _, err := os.Stat(filepath.Join(destDir, "b.txt")); !os.IsNotExist(err) {
+			t.Errorf("b.txt should not be extracted past the limit, got err %v", err)
+		}
+	})
+}
+
+func TestUnzip_Corrupt(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		write func(t *testing.T, w *zip.Writer)
+	}{
+		{
+			name: "truncated entry",
+			write: func(t *testing.T, w *zip.Writer) {
+				content := []byte("truncated")
+				// Declare more bytes than the entry holds, as if protoc was
+				// interrupted while writing it.
+				fw, err := w.CreateRaw(&zip.FileHeader{
+					Name:               "file.txt",
+					Method:             zip.Store,
+					CRC32:              crc32.ChecksumIEEE(content),
+					CompressedSize64:   uint64(len(content)),
+					UncompressedSize64: uint64(len(content)) + 100,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := fw.Write(content); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "duplicate entry",
+			write: func(t *testing.T, w *zip.Writer) {
+				for range 2 {
+					if _, err := w.Create("file.txt"); err != nil {
+						t.Fatal(err)
+					}
+				}
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			zipPath := filepath.Join(t.TempDir(), "corrupt.zip")
+			f, err := os.Create(zipPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			zipWriter := zip.NewWriter(f)
+			test.write(t, zipWriter)
+			if err := zipWriter.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			err = unzip(zipPath, t.TempDir(), generate.DefaultMaxExtractedSize)
+			if !errors.Is(err, errCorruptSrcjar) {
+				t.Errorf("unzip() error = %v, want %v", err, errCorruptSrcjar)
+			}
+		})
+	}
 }
 
 func TestMoveFiles(t *testing.T) {
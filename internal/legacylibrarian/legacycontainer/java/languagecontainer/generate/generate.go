@@ -24,6 +24,9 @@ import (
 	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/message"
 )
 
+// DefaultMaxExtractedSize is the default for Context.MaxExtractedSize.
+const DefaultMaxExtractedSize int64 = 4 << 30 // 4 GiB
+
 // Context holds the directory paths for the generate command.
 // https://github.com/googleapis/librarian/blob/main/doc/language-onboarding.md#generate
 type Context struct {
@@ -38,6 +41,14 @@ type Context struct {
 	OutputDir string
 	// SourceDir is the path to a complete checkout of the googleapis repository.
 	SourceDir string
+	// CommonResourcesProto is the path, relative to SourceDir, of the common
+	// resources proto compiled with every API. It is skipped if empty or
+	// missing.
+	CommonResourcesProto string
+	// MaxExtractedSize is the maximum total number of bytes extracted from a
+	// generated srcjar. It guards against corrupt or malicious archives
+	// expanding without bound. If zero, DefaultMaxExtractedSize is used.
+	MaxExtractedSize int64
 }
 
 // Validate ensures that the context is valid.
@@ -54,6 +65,9 @@ func (c *Context) Validate() error {
 	if c.SourceDir == "" {
 		return errors.New("languagecontainer: source directory must be set")
 	}
+	if c.MaxExtractedSize < 0 {
+		return errors.New("languagecontainer: max extracted size must not be negative")
+	}
 	return nil
 }
 
@@ -102,6 +102,16 @@ func TestNewConfig_validate(t *testing.T) {
 				OutputDir:    "out",
 			},
 		},
+		{
+			name: "negative max extracted size",
+			context: &Context{
+				LibrarianDir:     "librarian",
+				InputDir:         "in",
+				OutputDir:        "out",
+				SourceDir:        "source",
+				MaxExtractedSize: -1,
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -32,6 +32,7 @@ import (
 	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/languagecontainer/generate"
 	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/languagecontainer/release"
 	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/message"
+	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/protoc"
 )
 
 // LanguageContainer defines the functions for language-specific container operations.
@@ -87,6 +88,8 @@ func handleGenerate(ctx context.Context, flags []string, container *LanguageCont
 	generateFlags.StringVar(&genCtx.InputDir, "input", "/input", "Path to the .librarian/generator-input directory from the language repository.")
 	generateFlags.StringVar(&genCtx.OutputDir, "output", "/output", "Path to the empty directory where a language container writes its output.")
 	generateFlags.StringVar(&genCtx.SourceDir, "source", "/source", "Path to a complete checkout of the googleapis repository.")
+	generateFlags.StringVar(&genCtx.CommonResourcesProto, "common-resources-proto", protoc.DefaultCommonResourcesProto, "Path, relative to -source, of the common resources proto. Skipped if missing.")
+	generateFlags.Int64Var(&genCtx.MaxExtractedSize, "max-extracted-size", generate.DefaultMaxExtractedSize, "Maximum total number of bytes extracted from a generated srcjar.")
 	if err := generateFlags.Parse(flags); err != nil {
 		slog.Error("failed to parse flags", "error", err)
 		return 1
@@ -208,7 +208,7 @@ func TestRun_GenerateReadsContextArgs(t *testing.T) {
 	if err := os.Mkdir(sourceDir, 0755); err != nil {
 		t.Fatal(err)
 	}
-	args := []string{"generate", "-librarian", librarianDir, "-input", inputDir, "-output", outputDir, "-source", sourceDir}
+	args := []string{"generate", "-librarian", librarianDir, "-input", inputDir, "-output", outputDir, "-source", sourceDir, "-max-extracted-size", "1024"}
 	var gotConfig *generate.Config
 	container := LanguageContainer{
 		Generate: func(ctx context.Context, c *generate.Config) error {
@@ -231,6 +231,9 @@ func TestRun_GenerateReadsContextArgs(t *testing.T) {
 	if got, want := gotConfig.Context.SourceDir, sourceDir; got != want {
 		t.Errorf("gotConfig.Context.SourceDir = %q, want %q", got, want)
 	}
+	if got, want := gotConfig.Context.MaxExtractedSize, int64(1024); got != want {
+		t.Errorf("gotConfig.Context.MaxExtractedSize = %d, want %d", got, want)
+	}
 }
 
 func TestRun_unimplementedCommands(t *testing.T) {
@@ -29,11 +29,29 @@ import (
 // ReleaseStageRequest is the structure of the release-stage-request.json file.
 type ReleaseStageRequest struct {
 	Libraries []*Library `json:"libraries"`
+	// Final indicates that the versions being staged are final releases, so
+	// they are written without the "-SNAPSHOT" suffix.
+	Final bool `json:"final,omitempty"`
+	// DryRun indicates that no files should be written. Instead, the changes
+	// that would be made are reported in the response.
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 // ReleaseStageResponse is the structure of the release-stage-response.json file.
 type ReleaseStageResponse struct {
 	Error string `json:"error,omitempty"`
+	// Diffs lists the changes that would be made to each file. It is only
+	// populated for dry runs.
+	Diffs []*FileDiff `json:"diffs,omitempty"`
+}
+
+// FileDiff describes the changes to a single file.
+type FileDiff struct {
+	// Path is the path of the file, relative to the repository root.
+	Path string `json:"path"`
+	// Diff lists each changed line, prefixed with "-" for the old content
+	// and "+" for the new content.
+	Diff string `json:"diff"`
 }
 
 // Library is the combination of all the fields used by CLI requests and responses.
@@ -16,9 +16,13 @@ package pom
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/legacylibrarian/legacycontainer/java/message"
 )
 
 var (
@@ -26,9 +30,10 @@ var (
 )
 
 // UpdateVersions updates the versions of all pom.xml files in a given directory.
-// It appends the "-SNAPSHOT" suffix to the version given the version parameter.
-// If the directory is not present, this function creates it.
-func UpdateVersions(repoDir, sourcePath, outputDir, libraryID, version string) error {
+// If snapshot is true, it appends the "-SNAPSHOT" suffix to the version given
+// the version parameter. If the directory is not present, this function
+// creates it.
+func UpdateVersions(repoDir, sourcePath, outputDir, libraryID, version string, snapshot bool) error {
 	pomFiles, err := findPomFiles(sourcePath)
 	if err != nil {
 		return fmt.Errorf("failed to find pom files: %w", err)
@@ -39,41 +44,107 @@ func UpdateVersions(repoDir, sourcePath, outputDir, libraryID, version string) e
 			return fmt.Errorf("failed to get relative path for %s: %w", pomFile, err)
 		}
 		outputPomFile := filepath.Join(outputDir, relPath)
-		if err := os.MkdirAll(filepath.Dir(outputPomFile), 0755); err != nil {
-			return fmt.Errorf("failed to create output directory for %s: %w", outputPomFile, err)
-		}
-		if err := updateVersion(pomFile, outputPomFile, libraryID, version); err != nil {
+		updated, err := updateVersion(pomFile, outputPomFile, libraryID, version, snapshot)
+		if err != nil {
 			return fmt.Errorf("failed to update version in %s: %w", pomFile, err)
 		}
+		if !updated {
+			slog.Debug("no version to update", "file", pomFile, "libraryID", libraryID)
+		}
 	}
 	return nil
 }
 
+// DiffVersions reports the changes UpdateVersions would make to the pom.xml
+// files in sourcePath, without writing any files. Files that would not change
+// are omitted.
+func DiffVersions(repoDir, sourcePath, libraryID, version string, snapshot bool) ([]*message.FileDiff, error) {
+	pomFiles, err := findPomFiles(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pom files: %w", err)
+	}
+	var diffs []*message.FileDiff
+	for _, pomFile := range pomFiles {
+		relPath, err := filepath.Rel(repoDir, pomFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relative path for %s: %w", pomFile, err)
+		}
+		content, err := os.ReadFile(pomFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		newContent := replaceVersion(string(content), libraryID, version, snapshot)
+		if newContent == string(content) {
+			continue
+		}
+		diffs = append(diffs, &message.FileDiff{
+			Path: relPath,
+			Diff: diffLines(string(content), newContent),
+		})
+	}
+	return diffs, nil
+}
+
 // updateVersion updates the version in a single pom.xml file.
-// It appends the "-SNAPSHOT" suffix to the the version parameter.
-// The directory for outputPath must already exist.
-func updateVersion(inputPath, outputPath, libraryID, version string) error {
+// If snapshot is true, it appends the "-SNAPSHOT" suffix to the version
+// parameter.
+// If no version line in the file belongs to libraryID, nothing is written
+// and false is returned. Otherwise, the directory for outputPath is created
+// if necessary and the updated content is written there.
+func updateVersion(inputPath, outputPath, libraryID, version string, snapshot bool) (bool, error) {
 	content, err := os.ReadFile(inputPath)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return false, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	newContent := replaceVersion(string(content), libraryID, version, snapshot)
+	if newContent == string(content) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return false, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(newContent), 0644); err != nil {
+		return false, fmt.Errorf("failed to write file: %w", err)
 	}
+	return true, nil
+}
 
-	newContent := versionRegex.ReplaceAllStringFunc(string(content), func(s string) string {
+// replaceVersion returns content with every version annotated for libraryID
+// replaced by version, with the "-SNAPSHOT" suffix if snapshot is true.
+func replaceVersion(content, libraryID, version string, snapshot bool) string {
+	if snapshot {
+		version += "-SNAPSHOT"
+	}
+	return versionRegex.ReplaceAllStringFunc(content, func(s string) string {
 		matches := versionRegex.FindStringSubmatch(s)
 		if len(matches) > 4 && matches[4] == libraryID {
 			// matches[1] is "<version>"
 			// matches[2] is the old version
 			// matches[3] is " <!-- {x-version-update:libraryID:current} --> </version>"
 			// matches[4] is libraryID
-			return fmt.Sprintf("%s%s-SNAPSHOT%s", matches[1], version, matches[3])
+			return fmt.Sprintf("%s%s%s", matches[1], version, matches[3])
 		}
 		return s
 	})
+}
 
-	if err := os.WriteFile(outputPath, []byte(newContent), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+// diffLines returns the lines that differ between oldContent and newContent,
+// each old line prefixed with "-" and followed by the new line prefixed with
+// "+". Version replacement never adds or removes lines, so lines are compared
+// by position.
+func diffLines(oldContent, newContent string) string {
+	oldLines := strings.Split(oldContent, "\n")
+	newLines := strings.Split(newContent, "\n")
+	var b strings.Builder
+	for i := 0; i < len(oldLines) && i < len(newLines); i++ {
+		if oldLines[i] == newLines[i] {
+			continue
+		}
+		fmt.Fprintf(&b, "@@ line %d @@\n-%s\n+%s\n", i+1, oldLines[i], newLines[i])
 	}
-	return nil
+	return b.String()
 }
 
 func findPomFiles(path string) ([]string, error) {
@@ -26,7 +26,9 @@ func TestUpdateVersion(t *testing.T) {
 		initial     string
 		libraryID   string
 		version     string
+		final       bool
 		expected    string
+		wantUpdated bool
 		expectError bool
 	}{
 		{
@@ -39,17 +41,28 @@ func TestUpdateVersion(t *testing.T) {
 			expected: `<project>
   <version>2.0.0-SNAPSHOT</version><!-- {x-version-update:google-cloud-java:current} -->
 </project>`,
+			wantUpdated: true,
 		},
 		{
-			name: "no match",
+			name: "final version",
 			initial: `<project>
   <version>1.0.0-SNAPSHOT</version><!-- {x-version-update:google-cloud-java:current} -->
 </project>`,
-			libraryID: "wrong-library-id",
+			libraryID: "google-cloud-java",
 			version:   "2.0.0",
+			final:     true,
 			expected: `<project>
+  <version>2.0.0</version><!-- {x-version-update:google-cloud-java:current} -->
+</project>`,
+			wantUpdated: true,
+		},
+		{
+			name: "no match",
+			initial: `<project>
   <version>1.0.0-SNAPSHOT</version><!-- {x-version-update:google-cloud-java:current} -->
 </project>`,
+			libraryID: "wrong-library-id",
+			version:   "2.0.0",
 		},
 		{
 			name: "multiple versions",
@@ -71,6 +84,7 @@ func TestUpdateVersion(t *testing.T) {
     <version>2.0.0-SNAPSHOT</version><!-- {x-version-update:google-cloud-secretmanager:current} -->
   </dependency>
 </project>`,
+			wantUpdated: true,
 		},
 		{
 			name: "no comment",
@@ -79,9 +93,6 @@ func TestUpdateVersion(t *testing.T) {
 </project>`,
 			libraryID: "google-cloud-java",
 			version:   "2.0.0",
-			expected: `<project>
-  <version>1.0.0-SNAPSHOT</version>
-</project>`,
 		},
 	}
 
@@ -94,10 +105,7 @@ func TestUpdateVersion(t *testing.T) {
 				t.Fatalf("failed to write initial pom.xml: %v", err)
 			}
 
-			if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
-				t.Fatalf("failed to create output directory: %v", err)
-			}
-			err := updateVersion(pomPath, outPath, test.libraryID, test.version)
+			updated, err := updateVersion(pomPath, outPath, test.libraryID, test.version, !test.final)
 
 			if test.expectError {
 				if err == nil {
@@ -107,6 +115,15 @@ func TestUpdateVersion(t *testing.T) {
 				if err != nil {
 					t.Errorf("unexpected error: %v", err)
 				}
+				if updated != test.wantUpdated {
+					t.Errorf("updated = %v, want %v", updated, test.wantUpdated)
+				}
+				if !test.wantUpdated {
+					if _, err := os.Stat(outPath); !os.IsNotExist(err) {
+						t.Errorf("expected %s to not be written, got err %v", outPath, err)
+					}
+					return
+				}
 				content, readErr := os.ReadFile(outPath)
 				if readErr != nil {
 					t.Fatalf("failed to read pom.xml: %v", readErr)
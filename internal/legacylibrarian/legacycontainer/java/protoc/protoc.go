@@ -16,7 +16,9 @@
 package protoc
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -34,6 +36,10 @@ type ConfigProvider interface {
 	HasGAPIC() bool
 }
 
+// DefaultCommonResourcesProto is the path, relative to the googleapis source
+// directory, of the proto file that defines resources shared across Cloud APIs.
+const DefaultCommonResourcesProto = "google/cloud/common_resources.proto"
+
 // OutputConfig provides paths to directories to be used for protoc output.
 type OutputConfig struct {
 	GAPICDir string
@@ -42,7 +48,10 @@ type OutputConfig struct {
 }
 
 // Build constructs the full protoc command arguments for a given API.
-func Build(apiServiceDir string, config ConfigProvider, sourceDir string, outputConfig *OutputConfig) ([]string, error) {
+// commonResourcesProto is the path, relative to sourceDir, of the common
+// resources proto to compile alongside the API's protos. It is skipped if it is
+// empty or the file does not exist.
+func Build(apiServiceDir string, config ConfigProvider, sourceDir, commonResourcesProto string, outputConfig *OutputConfig) ([]string, error) {
 	// Gather all .proto files in the API's source directory.
 	entries, err := os.ReadDir(apiServiceDir)
 	if err != nil {
@@ -55,13 +64,22 @@ func Build(apiServiceDir string, config ConfigProvider, sourceDir string, output
 			protoFiles = append(protoFiles, filepath.Join(apiServiceDir, entry.Name()))
 		}
 	}
-	// Add common protos to the list of proto files to be compiled.
-	protoFiles = append(protoFiles, filepath.Join(sourceDir, "google", "cloud", "common_resources.proto"))
-
 	if len(protoFiles) == 0 {
 		return nil, fmt.Errorf("librariangen: no .proto files found in %s", apiServiceDir)
 	}
 
+	// Add common protos to the list of proto files to be compiled.
+	if commonResourcesProto != "" {
+		path := filepath.Join(sourceDir, filepath.FromSlash(commonResourcesProto))
+		_, err := os.Stat(path)
+		switch {
+		case err == nil:
+			protoFiles = append(protoFiles, path)
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("librariangen: failed to stat common resources proto %s: %w", path, err)
+		}
+	}
+
 	// Construct the protoc command arguments.
 	var gapicOpts []string
 	if config.HasGAPIC() {
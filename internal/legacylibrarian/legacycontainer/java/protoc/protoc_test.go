@@ -15,6 +15,7 @@
 package protoc
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -78,7 +79,6 @@ func TestBuild(t *testing.T) {
 				}, ","),
 				"-I=" + sourceDir,
 				filepath.Join(sourceDir, "google/cloud/workflows/v1/workflows.proto"),
-				filepath.Join(sourceDir, "google/cloud/common_resources.proto"),
 			},
 		},
 		{
@@ -105,7 +105,6 @@ func TestBuild(t *testing.T) {
 				}, ","),
 				"-I=" + sourceDir,
 				filepath.Join(sourceDir, "google/cloud/secretmanager/v1beta2/secretmanager.proto"),
-				filepath.Join(sourceDir, "google/cloud/common_resources.proto"),
 			},
 		}, {
 			// Note: we don't have a separate test directory with a proto-only library;
@@ -121,7 +120,6 @@ func TestBuild(t *testing.T) {
 				"--java_out=/output/proto",
 				"-I=" + sourceDir,
 				filepath.Join(sourceDir, "google/cloud/secretmanager/v1beta2/secretmanager.proto"),
-				filepath.Join(sourceDir, "google/cloud/common_resources.proto"),
 			},
 		},
 	}
@@ -133,7 +131,7 @@ func TestBuild(t *testing.T) {
 				GRPCDir:  "/output/grpc",
 				ProtoDir: "/output/proto",
 			}
-			got, err := Build(filepath.Join(sourceDir, test.apiPath), &test.config, sourceDir, outputConfig)
+			got, err := Build(filepath.Join(sourceDir, test.apiPath), &test.config, sourceDir, DefaultCommonResourcesProto, outputConfig)
 			if err != nil {
 				t.Fatalf("Build() failed: %v", err)
 			}
@@ -144,3 +142,69 @@ func TestBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestBuild_CommonResourcesProto(t *testing.T) {
+	sourceDir := t.TempDir()
+	for _, path := range []string{
+		"google/cloud/foo/v1/foo.proto",
+		"google/cloud/common_resources.proto",
+		"overlay/google/cloud/common_resources.proto",
+	} {
+		fullPath := filepath.Join(sourceDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(`syntax = "proto3";`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apiServiceDir := filepath.Join(sourceDir, "google/cloud/foo/v1")
+	for _, test := range []struct {
+		name                 string
+		commonResourcesProto string
+		want                 []string
+	}{
+		{
+			name:                 "default",
+			commonResourcesProto: DefaultCommonResourcesProto,
+			want: []string{
+				filepath.Join(apiServiceDir, "foo.proto"),
+				filepath.Join(sourceDir, "google/cloud/common_resources.proto"),
+			},
+		},
+		{
+			name:                 "configured path",
+			commonResourcesProto: "overlay/google/cloud/common_resources.proto",
+			want: []string{
+				filepath.Join(apiServiceDir, "foo.proto"),
+				filepath.Join(sourceDir, "overlay/google/cloud/common_resources.proto"),
+			},
+		},
+		{
+			name:                 "missing",
+			commonResourcesProto: "missing/common_resources.proto",
+			want:                 []string{filepath.Join(apiServiceDir, "foo.proto")},
+		},
+		{
+			name: "empty",
+			want: []string{filepath.Join(apiServiceDir, "foo.proto")},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			outputConfig := &OutputConfig{ProtoDir: "/output/proto"}
+			args, err := Build(apiServiceDir, &mockConfigProvider{}, sourceDir, test.commonResourcesProto, outputConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, arg := range args {
+				if strings.HasSuffix(arg, ".proto") {
+					got = append(got, arg)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -32,10 +32,20 @@ func Stage(ctx context.Context, cfg *release.Config) (*message.ReleaseStageRespo
 	for _, lib := range cfg.Request.Libraries {
 		for _, path := range lib.SourcePaths {
 			slog.Info("release-stage: processing library", "libraryID", lib.ID, "version", lib.Version, "sourcePath", path)
+			sourcePath := filepath.Join(cfg.Context.RepoDir, path)
+			if cfg.Request.DryRun {
+				diffs, err := pom.DiffVersions(cfg.Context.RepoDir, sourcePath, lib.ID, lib.Version, !cfg.Request.Final)
+				if err != nil {
+					response.Error = err.Error()
+					return response, err
+				}
+				response.Diffs = append(response.Diffs, diffs...)
+				continue
+			}
 			if err := pom.UpdateVersions(
 				cfg.Context.RepoDir,
-				filepath.Join(cfg.Context.RepoDir, path),
-				cfg.Context.OutputDir, lib.ID, lib.Version); err != nil {
+				sourcePath,
+				cfg.Context.OutputDir, lib.ID, lib.Version, !cfg.Request.Final); err != nil {
 				response.Error = err.Error()
 				return response, err
 			}
@@ -31,6 +31,7 @@ func TestStage(t *testing.T) {
 		libraryID   string
 		SourcePaths []string
 		version     string
+		final       bool
 		expected    string
 	}{
 		{
@@ -42,6 +43,16 @@ func TestStage(t *testing.T) {
 			version:  "2.0.0",
 			expected: "<version>2.0.0-SNAPSHOT</version><!-- {x-version-update:google-cloud-foo:current} -->",
 		},
+		{
+			name:      "final release",
+			libraryID: "google-cloud-foo",
+			SourcePaths: []string{
+				"java-foo",
+			},
+			version:  "2.0.0",
+			final:    true,
+			expected: "<version>2.0.0</version><!-- {x-version-update:google-cloud-foo:current} -->",
+		},
 		{
 			name:      "Source Paths not matching the folder",
 			libraryID: "google-cloud-java",
@@ -78,6 +89,7 @@ func TestStage(t *testing.T) {
 							SourcePaths: test.SourcePaths,
 						},
 					},
+					Final: test.final,
 				},
 			}
 
@@ -116,3 +128,48 @@ func TestStage(t *testing.T) {
 		})
 	}
 }
+
+func TestStage_DryRun(t *testing.T) {
+	outputDir := t.TempDir()
+	cfg := &release.Config{
+		Context: &release.Context{
+			RepoDir:   "testdata",
+			OutputDir: outputDir,
+		},
+		Request: &message.ReleaseStageRequest{
+			Libraries: []*message.Library{
+				{
+					ID:          "google-cloud-foo",
+					Version:     "2.0.0",
+					SourcePaths: []string{"java-foo"},
+				},
+			},
+			DryRun: true,
+		},
+	}
+
+	response, err := Stage(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("Stage() got unexpected error: %v", err)
+	}
+	if len(response.Diffs) != 2 {
+		t.Fatalf("got %d diffs, want 2", len(response.Diffs))
+	}
+	for _, diff := range response.Diffs {
+		for _, want := range []string{
+			"-    <version>1.0.0-SNAPSHOT</version>",
+			"+    <version>2.0.0-SNAPSHOT</version>",
+		} {
+			if !strings.Contains(diff.Diff, want) {
+				t.Errorf("diff for %s = %q, want it to contain %q", diff.Path, diff.Diff, want)
+			}
+		}
+	}
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("failed to read output directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no files in output directory, got %d files", len(entries))
+	}
+}
@@ -56,6 +56,7 @@ type Repository interface {
 	CheckoutCommitAndCreateBranch(name, commitHash string) error
 	NewAndDeletedFiles() ([]string, error)
 	Push(branchName string) error
+	RemoteBranchExists(branchName string) (bool, error)
 	Restore(paths []string) error
 	CleanUntracked(paths []string) error
 	pushRefSpec(refSpec string) error
@@ -571,28 +572,39 @@ func (r *LocalRepository) DeleteBranch(branchName string) error {
 	return r.pushRefSpec(refSpec)
 }
 
-func (r *LocalRepository) pushRefSpec(refSpec string) error {
-	slog.Info("pushing changes", "refSpec", refSpec)
-
-	// Check for the configured URI for the `origin` remote.
-	// If there are multiple URLs, the first one is selected.
-	var remoteURI string
-	remotes, err := r.Remotes()
+// RemoteBranchExists reports whether a branch named branchName exists on the
+// origin remote.
+func (r *LocalRepository) RemoteBranchExists(branchName string) (bool, error) {
+	remote, err := r.repo.Remote("origin")
 	if err != nil {
-		return err
+		return false, err
 	}
-	for _, remote := range remotes {
-		if remote.Name == "origin" {
-			if len(remote.URLs) > 0 {
-				remoteURI = remote.URLs[0]
-			}
+	auth, err := r.originAuth()
+	if err != nil {
+		return false, err
+	}
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	want := plumbing.NewBranchReferenceName(branchName)
+	for _, ref := range refs {
+		if ref.Name() == want {
+			return true, nil
 		}
 	}
+	return false, nil
+}
+
+func (r *LocalRepository) pushRefSpec(refSpec string) error {
+	slog.Info("pushing changes", "refSpec", refSpec)
 
-	useSSH := canUseSSH(remoteURI)
 	// While cloning a public repo does not require any authCreds, pushing
 	// to the repo requires authentication and verification of identity
-	auth, err := r.authCreds(useSSH)
+	auth, err := r.originAuth()
 	if err != nil {
 		return err
 	}
@@ -607,6 +619,24 @@ func (r *LocalRepository) pushRefSpec(refSpec string) error {
 	return nil
 }
 
+// originAuth returns the AuthMethod for the configured URI of the `origin`
+// remote. If there are multiple URLs, the first one is selected.
+func (r *LocalRepository) originAuth() (transport.AuthMethod, error) {
+	var remoteURI string
+	remotes, err := r.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	for _, remote := range remotes {
+		if remote.Name == "origin" {
+			if len(remote.URLs) > 0 {
+				remoteURI = remote.URLs[0]
+			}
+		}
+	}
+	return r.authCreds(canUseSSH(remoteURI))
+}
+
 // canUseSSH returns if the remote URI can connect via https ssh. It attempts to
 // automatically determine the type and returns false as a default if it's unable
 // to make a determination.
@@ -749,6 +749,62 @@ func TestRemotes(t *testing.T) {
 	}
 }
 
+func TestRemoteBranchExists(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name         string
+		emptyRemote  bool
+		remoteBranch string
+		branchName   string
+		want         bool
+	}{
+		{
+			name:         "branch exists",
+			remoteBranch: "librarian-20250701T123045Z-abc123",
+			branchName:   "librarian-20250701T123045Z-abc123",
+			want:         true,
+		},
+		{
+			name:         "branch does not exist",
+			remoteBranch: "librarian-20250701T123045Z-abc123",
+			branchName:   "librarian-20250701T123045Z-def456",
+		},
+		{
+			name:        "empty remote",
+			emptyRemote: true,
+			branchName:  "librarian-20250701T123045Z-abc123",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			remoteRepo, remoteDir := initTestRepo(t)
+			if !test.emptyRemote {
+				commit := createAndCommit(t, remoteRepo, "README.md", []byte("hello"), "initial commit")
+				ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(test.remoteBranch), commit.Hash)
+				if err := remoteRepo.Storer.SetReference(ref); err != nil {
+					t.Fatal(err)
+				}
+			}
+			gogitRepo, dir := initTestRepo(t)
+			if _, err := gogitRepo.CreateRemote(&goGitConfig.RemoteConfig{
+				Name: "origin",
+				URLs: []string{remoteDir},
+			}); err != nil {
+				t.Fatalf("CreateRemote failed: %v", err)
+			}
+
+			repo := &LocalRepository{Dir: dir, repo: gogitRepo}
+			got, err := repo.RemoteBranchExists(test.branchName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("RemoteBranchExists(%q) = %t, want %t", test.branchName, got, test.want)
+			}
+		})
+	}
+}
+
 func TestGetCommit(t *testing.T) {
 	t.Parallel()
 	setup := func(t *testing.T, dir string) string {
@@ -1228,6 +1284,28 @@ func TestGetCommitsForPathsSinceCommit(t *testing.T) {
 	}
 }
 
+func TestGetCommitsForPathsSinceCommit_SharedPrefix(t *testing.T) {
+	t.Parallel()
+	repo, dir := initTestRepo(t)
+	base := createAndCommit(t, repo, "README.md", []byte("readme"), "chore: initial commit")
+	createAndCommit(t, repo, "google/cloud/foo/v1/foo.proto", []byte("foo"), "feat: change foo/v1")
+	createAndCommit(t, repo, "google/cloud/foobar/v1/foobar.proto", []byte("foobar"), "feat: change foobar/v1")
+	createAndCommit(t, repo, "google/cloud/foo/v1beta/foo.proto", []byte("foo"), "feat: change foo/v1beta")
+	r := &LocalRepository{Dir: dir, repo: repo}
+
+	got, err := r.GetCommitsForPathsSinceCommit([]string{"google/cloud/foo/v1"}, base.Hash.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotMessages []string
+	for _, c := range got {
+		gotMessages = append(gotMessages, strings.Split(c.Message, "\n")[0])
+	}
+	if diff := cmp.Diff([]string{"feat: change foo/v1"}, gotMessages); diff != "" {
+		t.Errorf("GetCommitsForPathsSinceCommit() mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestGetCommitsForPathsSinceTag(t *testing.T) {
 	t.Parallel()
 	repo, _ := setupRepoForGetCommitsTest(t)
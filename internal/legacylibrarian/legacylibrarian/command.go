@@ -17,6 +17,8 @@ package legacylibrarian
 import (
 	"cmp"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -46,6 +48,31 @@ For each failed library, open a ticket in that library’s repository and then y
 `
 )
 
+// defaultBaseBranch is the base branch of created pull requests if none is
+// configured.
+const defaultBaseBranch = "main"
+
+var (
+	// errBranchExists is returned when the branch for generated changes
+	// already exists on the remote.
+	errBranchExists = errors.New("branch already exists on remote")
+	// errBaseBranchNotFound is returned when the base branch for a pull
+	// request does not exist on the remote.
+	errBaseBranchNotFound = errors.New("base branch not found on remote")
+	// errInvalidImage is returned when the container image reference does not
+	// follow the OCI reference grammar.
+	errInvalidImage = errors.New("invalid image reference")
+)
+
+// Patterns from the OCI distribution reference grammar, see
+// https://github.com/distribution/reference/blob/main/reference.go.
+var (
+	imageDomainRegex    = regexp.MustCompile(`^(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?$`)
+	imageComponentRegex = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	imageTagRegex       = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	imageDigestRegex    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
 type pullRequestType int
 
 const (
@@ -173,7 +200,10 @@ func newCommandRunner(cfg *legacyconfig.Config) (*commandRunner, error) {
 		return nil, err
 	}
 
-	image := deriveImage(cfg.Image, state)
+	image, err := deriveImage(cfg.Image, state)
+	if err != nil {
+		return nil, err
+	}
 
 	gitHubRepo, err := GetGitHubRepository(cfg, languageRepo)
 	if err != nil {
@@ -245,14 +275,51 @@ func cloneOrOpenRepo(workRoot, repo string, depth int, branch, ci string, gitPas
 	return githubRepo, nil
 }
 
-func deriveImage(imageOverride string, state *legacyconfig.LibrarianState) string {
-	if imageOverride != "" {
-		return imageOverride
+// deriveImage returns the container image to use: imageOverride if set,
+// otherwise the image recorded in state, pinned by digest when state has one.
+// It returns an error if the image is not a valid OCI reference. No image at
+// all is not an error.
+func deriveImage(imageOverride string, state *legacyconfig.LibrarianState) (string, error) {
+	image := imageOverride
+	if image == "" {
+		image = state.ImageReference()
 	}
-	if state == nil {
-		return ""
+	if image == "" {
+		return "", nil
+	}
+	if err := validateImage(image); err != nil {
+		return "", err
 	}
-	return state.Image
+	return image, nil
+}
+
+// validateImage checks image against the OCI reference grammar,
+// [domain/]path[:tag][@digest], and names the first invalid component.
+func validateImage(image string) error {
+	name, digest, hasDigest := strings.Cut(image, "@")
+	if hasDigest && !imageDigestRegex.MatchString(digest) {
+		return fmt.Errorf("%w %q: invalid digest %q", errInvalidImage, image, digest)
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		tag := name[i+1:]
+		name = name[:i]
+		if !imageTagRegex.MatchString(tag) {
+			return fmt.Errorf("%w %q: invalid tag %q", errInvalidImage, image, tag)
+		}
+	}
+	components := strings.Split(name, "/")
+	if len(components) > 1 && (strings.ContainsAny(components[0], ".:") || components[0] == "localhost") {
+		if !imageDomainRegex.MatchString(components[0]) {
+			return fmt.Errorf("%w %q: invalid registry %q", errInvalidImage, image, components[0])
+		}
+		components = components[1:]
+	}
+	for _, c := range components {
+		if !imageComponentRegex.MatchString(c) {
+			return fmt.Errorf("%w %q: invalid repository component %q", errInvalidImage, image, c)
+		}
+	}
+	return nil
 }
 
 func findLibraryIDByAPIPath(state *legacyconfig.LibrarianState, apiPath string) string {
@@ -402,8 +469,18 @@ func commitAndPush(ctx context.Context, info *commitInfo) error {
 		return fmt.Errorf("failed to add all files to git: %w", err)
 	}
 
-	datetimeNow := formatTimestamp(time.Now())
-	branch := fmt.Sprintf("librarian-%s", datetimeNow)
+	now := time.Now()
+	datetimeNow := formatTimestamp(now)
+	branch, err := newBranchName(now)
+	if err != nil {
+		return err
+	}
+	baseBranch := cmp.Or(info.branch, defaultBaseBranch)
+	if info.push {
+		if err := checkRemoteBranches(repo, branch, baseBranch); err != nil {
+			return err
+		}
+	}
 	if err := repo.CreateBranchAndCheckout(branch); err != nil {
 		return fmt.Errorf("failed to create branch and checkout: %w", err)
 	}
@@ -432,7 +509,7 @@ func commitAndPush(ctx context.Context, info *commitInfo) error {
 		return fmt.Errorf("failed to create pull request body: %w", err)
 	}
 
-	pullRequestMetadata, err := info.ghClient.CreatePullRequest(ctx, gitHubRepo, branch, info.branch, title, prBody, info.isDraft)
+	pullRequestMetadata, err := info.ghClient.CreatePullRequest(ctx, gitHubRepo, branch, baseBranch, title, prBody, info.isDraft)
 	if err != nil {
 		return fmt.Errorf("failed to create pull request: %w", err)
 	}
@@ -446,6 +523,42 @@ func commitAndPush(ctx context.Context, info *commitInfo) error {
 	return addLabelsToPullRequest(ctx, info.ghClient, info.pullRequestLabels, pullRequestMetadata)
 }
 
+// checkRemoteBranches verifies, before anything is pushed, that the pull
+// request base branch exists on the remote and the branch for the generated
+// changes does not.
+func checkRemoteBranches(repo legacygitrepo.Repository, branch, baseBranch string) error {
+	exists, err := repo.RemoteBranchExists(baseBranch)
+	if err != nil {
+		return fmt.Errorf("failed to check for remote branch: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %q; use -branch to choose the pull request base", errBaseBranchNotFound, baseBranch)
+	}
+	exists, err = repo.RemoteBranchExists(branch)
+	if err != nil {
+		return fmt.Errorf("failed to check for remote branch: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", errBranchExists, branch)
+	}
+	return nil
+}
+
+// branchSuffixSource supplies the random bytes of branch name suffixes. Tests
+// replace it to make branch names deterministic.
+var branchSuffixSource io.Reader = rand.Reader
+
+// newBranchName returns the name of the branch to commit generated changes to.
+// The timestamp is only precise to the second, so a random suffix keeps
+// branches created by runs within the same second, or reruns, apart.
+func newBranchName(now time.Time) (string, error) {
+	suffix := make([]byte, 3)
+	if _, err := io.ReadFull(branchSuffixSource, suffix); err != nil {
+		return "", fmt.Errorf("failed to generate branch name: %w", err)
+	}
+	return fmt.Sprintf("librarian-%s-%s", formatTimestamp(now), hex.EncodeToString(suffix)), nil
+}
+
 // writePRBody attempts to log the body of a PR that would have been created if the
 // -push flag had been specified. This logs any errors and returns them to the
 // caller.
@@ -517,6 +630,9 @@ func copyGlobalAllowlist(cfg *legacyconfig.LibrarianConfig, dst, src string, cop
 	return nil
 }
 
+// copyFile copies src to dst, creating the parent directories of dst as
+// needed. Symlinks are recreated rather than followed, and the permissions of
+// regular files are preserved.
 func copyFile(dst, src string) (err error) {
 	lstat, err := os.Lstat(src)
 	if err != nil {
@@ -553,6 +669,12 @@ func copyFile(dst, src string) (err error) {
 	}
 	defer destinationFile.Close()
 
+	// Preserve the permissions of the source file, so that generated scripts
+	// remain executable.
+	if err := destinationFile.Chmod(lstat.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions on file: %q: %w", dst, err)
+	}
+
 	_, err = io.Copy(destinationFile, sourceFile)
 
 	return err
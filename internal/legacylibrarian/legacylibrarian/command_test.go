@@ -15,8 +15,10 @@
 package legacylibrarian
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -63,10 +65,39 @@ func TestDeriveImage(t *testing.T) {
 			state:         &legacyconfig.LibrarianState{Image: "gcr.io/foo/bar:v1.2.3"},
 			want:          "gcr.io/foo/bar:v1.2.3",
 		},
+		{
+			name:          "no override, digest only",
+			imageOverride: "",
+			state: &legacyconfig.LibrarianState{
+				Image:       "gcr.io/foo/bar",
+				ImageDigest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			want: "gcr.io/foo/bar@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		},
+		{
+			name:          "no override, tag and digest",
+			imageOverride: "",
+			state: &legacyconfig.LibrarianState{
+				Image:       "gcr.io/foo/bar:v1.2.3",
+				ImageDigest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			want: "gcr.io/foo/bar@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		},
+		{
+			name:          "with image override, state digest ignored",
+			imageOverride: "my/custom-image:v1",
+			state: &legacyconfig.LibrarianState{
+				Image:       "gcr.io/foo/bar:v1.2.3",
+				ImageDigest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			want: "my/custom-image:v1",
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			got := deriveImage(test.imageOverride, test.state)
-
+			got, err := deriveImage(test.imageOverride, test.state)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if got != test.want {
 				t.Errorf("deriveImage() = %q, want %q", got, test.want)
 			}
@@ -74,6 +105,86 @@ func TestDeriveImage(t *testing.T) {
 	}
 }
 
+func TestDeriveImage_Valid(t *testing.T) {
+	for _, image := range []string{
+		"gcr.io/foo/bar:v1.2.3",
+		"us-central1-docker.pkg.dev/cloud-sdk-librarian-prod/images-prod/python-librarian-generator:latest",
+		"localhost:5000/librarian/generator",
+		"python-librarian-generator",
+		"gcr.io/foo/bar@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		"gcr.io/foo/bar:v1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	} {
+		t.Run(image, func(t *testing.T) {
+			got, err := deriveImage(image, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != image {
+				t.Errorf("deriveImage() = %q, want %q", got, image)
+			}
+		})
+	}
+}
+
+func TestDeriveImage_Error(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		image   string
+		wantMsg string
+	}{
+		{
+			name:    "empty tag",
+			image:   "gcr.io/foo/bar:",
+			wantMsg: `invalid tag ""`,
+		},
+		{
+			name:    "tag with space",
+			image:   "gcr.io/foo/bar:v1 2",
+			wantMsg: `invalid tag "v1 2"`,
+		},
+		{
+			name:    "tag with illegal character",
+			image:   "gcr.io/foo/bar:v1$",
+			wantMsg: `invalid tag "v1$"`,
+		},
+		{
+			name:    "uppercase repository",
+			image:   "gcr.io/Foo/bar:v1",
+			wantMsg: `invalid repository component "Foo"`,
+		},
+		{
+			name:    "invalid registry",
+			image:   "gcr_io.example/foo:v1",
+			wantMsg: `invalid registry "gcr_io.example"`,
+		},
+		{
+			name:    "invalid digest",
+			image:   "gcr.io/foo/bar@sha256:xyz",
+			wantMsg: `invalid digest "sha256:xyz"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// Invalid images are rejected whether they come from the
+			// override or from the state file.
+			for _, args := range []struct {
+				override string
+				state    *legacyconfig.LibrarianState
+			}{
+				{override: test.image},
+				{state: &legacyconfig.LibrarianState{Image: test.image}},
+			} {
+				_, err := deriveImage(args.override, args.state)
+				if !errors.Is(err, errInvalidImage) {
+					t.Fatalf("want error %v, got %v", errInvalidImage, err)
+				}
+				if !strings.Contains(err.Error(), test.wantMsg) {
+					t.Errorf("error %q does not contain %q", err, test.wantMsg)
+				}
+			}
+		})
+	}
+}
+
 // newTestGitRepoWithCommit creates a new git repository with an initial commit.
 // If dir is empty, a new temporary directory is created.
 // It returns the path to the repository directory.
@@ -1239,6 +1350,72 @@ func TestCompileRegexps(t *testing.T) {
 	}
 }
 
+func TestNewBranchName(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 30, 45, 0, time.UTC)
+	for _, test := range []struct {
+		name    string
+		source  io.Reader
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "suffix from source",
+			source: bytes.NewReader([]byte{0x0a, 0xbc, 0xde, 0xff}),
+			want:   "librarian-20250701T123045Z-0abcde",
+		},
+		{
+			name:    "source exhausted",
+			source:  bytes.NewReader([]byte{0x0a}),
+			wantErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			defer func(source io.Reader) { branchSuffixSource = source }(branchSuffixSource)
+			branchSuffixSource = test.source
+			got, err := newBranchName(now)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("newBranchName() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("newBranchName() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCommitAndPush_BaseBranch(t *testing.T) {
+	const baseBranch = "release-1.x"
+	repo := &MockRepository{
+		Dir: t.TempDir(),
+		RemotesValue: []*legacygitrepo.Remote{
+			{
+				Name: "origin",
+				URLs: []string{"https://github.com/googleapis/librarian.git"},
+			},
+		},
+		RemoteBranchExistsFunc: func(name string) bool { return name == baseBranch },
+	}
+	client := &mockGitHubClient{
+		createdPR: &legacygithub.PullRequestMetadata{Number: 123, Repo: &legacygithub.Repository{Owner: "test-owner", Name: "test-repo"}},
+	}
+	info := &commitInfo{
+		branch:        baseBranch,
+		ghClient:      client,
+		prType:        pullRequestGenerate,
+		push:          true,
+		languageRepo:  repo,
+		state:         &legacyconfig.LibrarianState{},
+		workRoot:      t.TempDir(),
+		prBodyBuilder: func() (string, error) { return "some pr body", nil },
+	}
+	if err := commitAndPush(t.Context(), info); err != nil {
+		t.Fatal(err)
+	}
+	if client.createPullRequestBase != baseBranch {
+		t.Errorf("CreatePullRequest() base = %q, want %q", client.createPullRequestBase, baseBranch)
+	}
+}
+
 func TestCommitAndPush(t *testing.T) {
 	for _, test := range []struct {
 		name              string
@@ -1434,6 +1611,69 @@ func TestCommitAndPush(t *testing.T) {
 			wantErr:        true,
 			expectedErrMsg: "create branch error",
 		},
+		{
+			name: "Branch exists on remote",
+			setupMockRepo: func(t *testing.T) legacygitrepo.Repository {
+				remote := &legacygitrepo.Remote{
+					Name: "origin",
+					URLs: []string{"https://github.com/googleapis/librarian.git"},
+				}
+				return &MockRepository{
+					Dir:                    t.TempDir(),
+					RemotesValue:           []*legacygitrepo.Remote{remote},
+					RemoteBranchExistsFunc: func(name string) bool { return true },
+				}
+			},
+			setupMockClient: func(t *testing.T) GitHubClient {
+				return nil
+			},
+			prType:         pullRequestGenerate,
+			push:           true,
+			wantErr:        true,
+			expectedErrMsg: errBranchExists.Error(),
+		},
+		{
+			name: "Base branch not found on remote",
+			setupMockRepo: func(t *testing.T) legacygitrepo.Repository {
+				remote := &legacygitrepo.Remote{
+					Name: "origin",
+					URLs: []string{"https://github.com/googleapis/librarian.git"},
+				}
+				return &MockRepository{
+					Dir:                    t.TempDir(),
+					RemotesValue:           []*legacygitrepo.Remote{remote},
+					RemoteBranchExistsFunc: func(name string) bool { return false },
+				}
+			},
+			setupMockClient: func(t *testing.T) GitHubClient {
+				return nil
+			},
+			prType:         pullRequestGenerate,
+			push:           true,
+			wantErr:        true,
+			expectedErrMsg: errBaseBranchNotFound.Error(),
+		},
+		{
+			name: "Remote branch check error",
+			setupMockRepo: func(t *testing.T) legacygitrepo.Repository {
+				remote := &legacygitrepo.Remote{
+					Name: "origin",
+					URLs: []string{"https://github.com/googleapis/librarian.git"},
+				}
+				return &MockRepository{
+					Dir:                     t.TempDir(),
+					RemotesValue:            []*legacygitrepo.Remote{remote},
+					RemoteBranchExistsError: errors.New("list remote error"),
+				}
+			},
+			setupMockClient: func(t *testing.T) GitHubClient {
+				return nil
+			},
+			prType:         pullRequestGenerate,
+			push:           true,
+			wantErr:        true,
+			expectedErrMsg: "list remote error",
+		},
 		{
 			name: "Commit error",
 			setupMockRepo: func(t *testing.T) legacygitrepo.Repository {
@@ -2133,6 +2373,50 @@ func TestCopyFile(t *testing.T) {
 	}
 }
 
+func TestCopyLibraryFiles_PreservesMode(t *testing.T) {
+	t.Parallel()
+	src := t.TempDir()
+	dst := t.TempDir()
+	state := &legacyconfig.LibrarianState{
+		Libraries: []*legacyconfig.LibraryState{
+			{
+				ID:          "example-library",
+				SourceRoots: []string{"a/path"},
+			},
+		},
+	}
+	files := map[string]os.FileMode{
+		"a/path/script.sh":        0755,
+		"a/path/nested/README.md": 0644,
+	}
+	for name, mode := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), mode); err != nil {
+			t.Fatal(err)
+		}
+		// Apply the mode explicitly so the test does not depend on umask.
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := copyLibraryFiles(state, dst, "example-library", src, true); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range files {
+		info, err := os.Stat(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("mode of %s = %v, want %v", name, got, want)
+		}
+	}
+}
+
 func TestCopyGlobalAllowlist(t *testing.T) {
 	t.Parallel()
 	for _, test := range []struct {
@@ -250,7 +250,7 @@ func (r *generateRunner) generateSingleLibrary(ctx context.Context, libraryID, o
 
 	image := r.image
 	if image == "" {
-		image = r.state.Image
+		image = r.state.ImageReference()
 		slog.Info("using image from state", "image", image)
 	} else {
 		slog.Info("using image from command line", "image", image)
@@ -60,6 +60,9 @@ type mockGitHubClient struct {
 	releaseNames            []string
 	librarianState          *legacyconfig.LibrarianState
 	librarianConfig         *legacyconfig.LibrarianConfig
+	// createPullRequestBase is the base branch of the last created pull
+	// request.
+	createPullRequestBase string
 }
 
 func (m *mockGitHubClient) GetRawContent(ctx context.Context, path, ref string) ([]byte, error) {
@@ -75,6 +78,7 @@ func (m *mockGitHubClient) GetRawContent(ctx context.Context, path, ref string)
 
 func (m *mockGitHubClient) CreatePullRequest(ctx context.Context, repo *legacygithub.Repository, remoteBranch, remoteBase, title, body string, isDraft bool) (*legacygithub.PullRequestMetadata, error) {
 	m.createPullRequestCalls++
+	m.createPullRequestBase = remoteBase
 	if m.createPullRequestErr != nil {
 		return nil, m.createPullRequestErr
 	}
@@ -359,6 +363,7 @@ type MockRepository struct {
 	CheckoutCommitAndCreateBranchError     error
 	PushCalls                              int
 	PushError                              error
+	RemoteBranchExistsError                error
 	RestoreError                           error
 	HeadHashValue                          string
 	HeadHashError                          error
@@ -375,6 +380,9 @@ type MockRepository struct {
 	GetHashForPathValue map[string]string
 	ResetSoftCalls      int
 	ResetSoftError      error
+	// RemoteBranchExistsFunc reports whether a branch exists on the remote.
+	// If nil, only the "main" branch exists.
+	RemoteBranchExistsFunc func(name string) bool
 }
 
 func (m *MockRepository) HeadHash() (string, error) {
@@ -528,6 +536,16 @@ func (m *MockRepository) Push(name string) error {
 	return nil
 }
 
+func (m *MockRepository) RemoteBranchExists(name string) (bool, error) {
+	if m.RemoteBranchExistsError != nil {
+		return false, m.RemoteBranchExistsError
+	}
+	if m.RemoteBranchExistsFunc != nil {
+		return m.RemoteBranchExistsFunc(name), nil
+	}
+	return name == "main", nil
+}
+
 func (m *MockRepository) Restore(paths []string) error {
 	return m.RestoreError
 }
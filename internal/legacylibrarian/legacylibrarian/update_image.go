@@ -110,6 +110,8 @@ func (r *updateImageRunner) run(ctx context.Context) error {
 	}
 
 	r.state.Image = r.image
+	// A digest pins the previous image, so it no longer applies.
+	r.state.ImageDigest = ""
 
 	if err := saveLibrarianState(r.repo.GetDir(), r.state); err != nil {
 		return err
@@ -18,6 +18,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -42,6 +46,13 @@ var (
 	errBothVersionAndAllFlag = errors.New("cannot specify both --version and --all")
 	errReleaseCommitNotFound = errors.New("no release commit found")
 	errReleaseConfigEmpty    = errors.New("release config not set in librarian.yaml")
+	errSkipRelease           = errors.New("library has skip_release set")
+
+	// conventionalCommitHeader matches the first line of a Conventional
+	// Commits message, capturing the type and the breaking change marker.
+	conventionalCommitHeader = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?(!)?: `)
+	// breakingChangeFooter matches a footer that marks a breaking change.
+	breakingChangeFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
 
 	// languageVersioningOptions contains language-specific SemVer versioning
 	// options. Over time, languages should align on versioning semantics and
@@ -63,8 +74,13 @@ func bumpCommand() *cli.Command {
 		Description: `bump updates version numbers and prepares the files needed for a new release.
 
 If a library name is given, only that library is updated. The --all flag updates every
-library in the workspace. When a library is specified explicitly, the --version flag can
-be used to override the new version.
+library in the workspace. When a library is specified explicitly, the --version flag, or its
+alias --set, can be used to override the new version.
+
+Otherwise the new version is derived from the commits that changed the library
+since the last release, following Conventional Commits: a breaking change
+bumps the major version, a "feat" commit bumps the minor version, and any other
+change bumps the patch version.
 
 Examples:
   librarian bump <library>           # update version for one library
@@ -75,8 +91,9 @@ Examples:
 				Usage: "update all libraries in the workspace",
 			},
 			&cli.StringFlag{
-				Name:  "version",
-				Usage: "specific version to update to; not valid with --all",
+				Name:    "version",
+				Aliases: []string{"set"},
+				Usage:   "specific version to update to; not valid with --all",
 			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
@@ -112,6 +129,12 @@ func runBump(ctx context.Context, cfg *config.Config, all bool, libraryName, ver
 	if cfg.Release == nil {
 		return errReleaseConfigEmpty
 	}
+	if err := releasePreflight(cfg.Release); err != nil {
+		return err
+	}
+	if err := logReleaseTarget(ctx, gitExe, cfg.Release); err != nil {
+		return err
+	}
 	lastTag, err := git.GetLastTag(ctx, gitExe, cfg.Release.Remote, cfg.Release.Branch)
 	if err != nil {
 		return err
@@ -126,7 +149,19 @@ func runBump(ctx context.Context, cfg *config.Config, all bool, libraryName, ver
 		if err != nil {
 			return err
 		}
-		if err := bumpLibrary(ctx, cfg, lib, lastTag, gitExe, versionOverride); err != nil {
+		if lib.SkipRelease {
+			return fmt.Errorf("%w: %q", errSkipRelease, libraryName)
+		}
+		// The change level is only needed to derive the version when it is
+		// not overridden.
+		changes := semver.None
+		if versionOverride == "" {
+			changes, err = libraryChangeLevel(ctx, cfg, lib, lastTag, gitExe)
+			if err != nil {
+				return err
+			}
+		}
+		if err := bumpLibrary(ctx, cfg, lib, lastTag, gitExe, versionOverride, changes); err != nil {
 			return err
 		}
 	}
@@ -138,7 +173,7 @@ func runBump(ctx context.Context, cfg *config.Config, all bool, libraryName, ver
 }
 
 func bumpAll(ctx context.Context, cfg *config.Config, lastTag, gitExe string) error {
-	filesChanged, err := git.FilesChangedSince(ctx, lastTag, gitExe, cfg.Release.IgnoredChanges)
+	filesChanged, err := git.FilesChangedSince(ctx, lastTag, gitExe, ignoredChanges(cfg))
 	if err != nil {
 		return err
 	}
@@ -146,11 +181,18 @@ func bumpAll(ctx context.Context, cfg *config.Config, lastTag, gitExe string) er
 		if lib.SkipPublish {
 			continue
 		}
-		output := libraryOutput(cfg.Language, lib, cfg.Default)
-		if !hasChangesIn(output, filesChanged) {
+		if lib.SkipRelease {
+			slog.Info("skipping library with skip_release set", "library", lib.Name)
 			continue
 		}
-		if err := bumpLibrary(ctx, cfg, lib, lastTag, gitExe, ""); err != nil {
+		if !hasChangesIn(libraryDir(cfg.Language, lib, cfg.Default), filesChanged) {
+			continue
+		}
+		changes, err := libraryChangeLevel(ctx, cfg, lib, lastTag, gitExe)
+		if err != nil {
+			return err
+		}
+		if err := bumpLibrary(ctx, cfg, lib, lastTag, gitExe, "", changes); err != nil {
 			return err
 		}
 	}
@@ -169,9 +211,56 @@ func hasChangesIn(dir string, filesChanged []string) bool {
 	return false
 }
 
-func bumpLibrary(ctx context.Context, cfg *config.Config, lib *config.Library, lastTag, gitExe, versionOverride string) error {
+// libraryChangeLevel returns the level of the changes to lib since lastTag,
+// derived from the messages of the commits that touched the library
+// directory. Commits that only touched files matching Release.IgnoredChanges
+// are not considered. For Go this is the directory named after the library, not the
+// repository root shared by every library.
+func libraryChangeLevel(ctx context.Context, cfg *config.Config, lib *config.Library, lastTag, gitExe string) (semver.ChangeLevel, error) {
+	dir := libraryDir(cfg.Language, lib, cfg.Default)
+	messages, err := git.CommitMessagesForPathSince(ctx, gitExe, lastTag, dir, ignoredChanges(cfg))
+	if err != nil {
+		return semver.None, err
+	}
+	return changeLevel(messages), nil
+}
+
+// ignoredChanges returns the globs of files that are ignored in change
+// analysis, or nil if cfg has no release configuration.
+func ignoredChanges(cfg *config.Config) []string {
+	if cfg.Release == nil {
+		return nil
+	}
+	return cfg.Release.IgnoredChanges
+}
+
+// changeLevel returns the highest level of change described by the given
+// commit messages. Breaking changes are [semver.Major], "feat" commits are
+// [semver.Minor] and every other commit, including ones that do not follow
+// Conventional Commits, is [semver.Patch]. With no commits it returns
+// [semver.Patch], as an explicitly requested bump must change the version.
+func changeLevel(messages []string) semver.ChangeLevel {
+	level := semver.Patch
+	for _, message := range messages {
+		if breakingChangeFooter.MatchString(message) {
+			return semver.Major
+		}
+		header, _, _ := strings.Cut(message, "\n")
+		m := conventionalCommitHeader.FindStringSubmatch(header)
+		switch {
+		case m == nil:
+		case m[2] == "!":
+			return semver.Major
+		case m[1] == "feat":
+			level = semver.Minor
+		}
+	}
+	return level
+}
+
+func bumpLibrary(ctx context.Context, cfg *config.Config, lib *config.Library, lastTag, gitExe, versionOverride string, changes semver.ChangeLevel) error {
 	opts := languageVersioningOptions[cfg.Language]
-	version, err := deriveNextVersion(ctx, gitExe, cfg, lib, opts, versionOverride)
+	version, err := deriveNextVersion(ctx, gitExe, cfg, lib, opts, versionOverride, changes)
 	if err != nil {
 		return err
 	}
@@ -215,7 +304,7 @@ func findLibrary(c *config.Config, name string) (*config.Library, error) {
 	return nil, fmt.Errorf("%w: %q", ErrLibraryNotFound, name)
 }
 
-func deriveNextVersion(ctx context.Context, gitExe string, cfg *config.Config, libConfig *config.Library, opts semver.DeriveNextOptions, versionOverride string) (string, error) {
+func deriveNextVersion(ctx context.Context, gitExe string, cfg *config.Config, libConfig *config.Library, opts semver.DeriveNextOptions, versionOverride string, changes semver.ChangeLevel) (string, error) {
 	// If a version override has been specified, use it - but
 	// check that it's not a regression or a no-op.
 	if versionOverride != "" {
@@ -244,15 +333,11 @@ func deriveNextVersion(ctx context.Context, gitExe string, cfg *config.Config, l
 		return semver.DeriveNextPreview(libConfig.Version, stableVersion, opts)
 	}
 
-	return semver.DeriveNext(semver.Minor, libConfig.Version, opts)
+	return semver.DeriveNext(changes, libConfig.Version, opts)
 }
 
 func loadBranchLibraryVersion(ctx context.Context, gitExe, remote, branch, libName string) (string, error) {
-	branchLibrarianCfgFile, err := git.ShowFileAtRemoteBranch(ctx, gitExe, remote, branch, librarianConfigPath)
-	if err != nil {
-		return "", err
-	}
-	branchLibrarianCfg, err := yaml.Unmarshal[config.Config]([]byte(branchLibrarianCfgFile))
+	branchLibrarianCfg, err := readConfigAtRevision(ctx, gitExe, fmt.Sprintf("%s/%s", remote, branch), configPath(ctx))
 	if err != nil {
 		return "", err
 	}
@@ -263,6 +348,54 @@ func loadBranchLibraryVersion(ctx context.Context, gitExe, remote, branch, libNa
 	return branchLibCfg.Version, nil
 }
 
+// readConfigAtRevision reads the librarian configuration at path as of the
+// given revision, including the libraries from the files that matched
+// libraries_include at that revision. The path may be absolute or relative to
+// the current directory.
+func readConfigAtRevision(ctx context.Context, gitExe, revision, configFile string) (*config.Config, error) {
+	repoPath, err := git.RepoRelativePath(ctx, gitExe, configFile)
+	if err != nil {
+		return nil, err
+	}
+	content, err := git.ShowFileAtRevision(ctx, gitExe, revision, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := yaml.Unmarshal[config.Config]([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.LibrariesInclude) == 0 {
+		return cfg, nil
+	}
+	dir := path.Dir(repoPath)
+	files, err := git.ListFilesAtRevision(ctx, gitExe, revision, dir)
+	if err != nil {
+		return nil, err
+	}
+	glob := func(pattern string) ([]string, error) {
+		var matches []string
+		for _, file := range files {
+			matched, err := path.Match(path.Join(dir, filepath.ToSlash(pattern)), file)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				matches = append(matches, file)
+			}
+		}
+		return matches, nil
+	}
+	readFile := func(name string) ([]byte, error) {
+		content, err := git.ShowFileAtRevision(ctx, gitExe, revision, name)
+		return []byte(content), err
+	}
+	if err := cfg.IncludeLibrariesFunc(glob, readFile); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 // findReleasedLibraries determines which libraries are released by the
 // change in config from cfgBefore to cfgAfter. This includes libraries
 // which exist (with a version) in cfgAfter but either didn't exist or
@@ -307,7 +440,7 @@ func findReleasedLibraries(cfgBefore, cfgAfter *config.Config) ([]string, error)
 // if libraryName is empty. (See findReleasedLibraries for the definition of what it
 // means for a commit to release a library.)
 func findLatestReleaseCommitHash(ctx context.Context, gitExe, libraryName string) (string, error) {
-	commits, err := git.FindCommitsForPath(ctx, gitExe, librarianConfigPath)
+	commits, err := git.FindCommitsForPath(ctx, gitExe, configPath(ctx))
 	if err != nil {
 		return "", err
 	}
@@ -317,11 +450,7 @@ func findLatestReleaseCommitHash(ctx context.Context, gitExe, libraryName string
 	var candidateConfig *config.Config
 	candidateCommit := ""
 	for _, commit := range commits {
-		commitCfgContent, err := git.ShowFileAtRevision(ctx, gitExe, commit, librarianConfigPath)
-		if err != nil {
-			return "", err
-		}
-		commitCfg, err := yaml.Unmarshal[config.Config]([]byte(commitCfgContent))
+		commitCfg, err := readConfigAtRevision(ctx, gitExe, commit, configPath(ctx))
 		if err != nil {
 			return "", err
 		}
@@ -62,6 +62,12 @@ func TestBumpCommand(t *testing.T) {
 			withChanges:  []string{lib1Change},
 			wantVersions: map[string]string{sample.Lib1Name: "1.2.3"},
 		},
+		{
+			name:         "library name and set version",
+			args:         []string{"librarian", "bump", sample.Lib1Name, "--set=2.0.0"},
+			withChanges:  []string{lib1Change},
+			wantVersions: map[string]string{sample.Lib1Name: "2.0.0"},
+		},
 		{
 			name:        "all flag all have changes",
 			args:        []string{"librarian", "bump", "--all"},
@@ -210,12 +216,23 @@ func TestBumpCommand_Error(t *testing.T) {
 			}(),
 			wantErr: errReleaseConfigEmpty,
 		},
+		{
+			name: "library has skip_release set",
+			args: []string{"librarian", "bump", sample.Lib1Name},
+			cfg: func() *config.Config {
+				c := sample.Config()
+				c.Libraries[0].SkipRelease = true
+				return c
+			}(),
+			wantErr: errSkipRelease,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			testhelper.Setup(t, testhelper.SetupOptions{
 				Clone:  "main",
 				Config: test.cfg,
 				Dirty:  test.dirty,
+				Tag:    sample.InitialTag,
 			})
 
 			err := Run(t.Context(), test.args...)
@@ -344,7 +361,7 @@ func TestBumpLibrary(t *testing.T) {
 
 			targetLibCfg := targetCfg.Libraries[0]
 			// Unused string param: lastTag.
-			err := bumpLibrary(t.Context(), targetCfg, targetLibCfg, testUnusedStringParam, "git", test.versionOverride)
+			err := bumpLibrary(t.Context(), targetCfg, targetLibCfg, testUnusedStringParam, "git", test.versionOverride, semver.Minor)
 			if err != nil {
 				t.Fatalf("bumpLibrary() error = %v", err)
 			}
@@ -367,6 +384,29 @@ func TestBumpAll(t *testing.T) {
 		skipPublish bool
 		wantVersion string
 	}{
+		{
+			name: "library only has ignored changes",
+			cfg: func() *config.Config {
+				c := sample.Config()
+				c.Release.IgnoredChanges = []string{"Cargo.toml"}
+				return c
+			}(),
+			withChanges: []string{filepath.Join(sample.Lib1Output, "Cargo.toml")},
+			wantVersion: sample.InitialVersion,
+		},
+		{
+			name: "library has ignored and real changes",
+			cfg: func() *config.Config {
+				c := sample.Config()
+				c.Release.IgnoredChanges = []string{"Cargo.toml"}
+				return c
+			}(),
+			withChanges: []string{
+				filepath.Join(sample.Lib1Output, "Cargo.toml"),
+				filepath.Join(sample.Lib1Output, "src", "lib.rs"),
+			},
+			wantVersion: sample.NextVersion,
+		},
 		{
 			name:        "library has changes",
 			cfg:         sample.Config(),
@@ -378,6 +418,16 @@ func TestBumpAll(t *testing.T) {
 			cfg:         sample.Config(),
 			wantVersion: sample.InitialVersion,
 		},
+		{
+			name: "library has changes but skipRelease is true",
+			cfg: func() *config.Config {
+				c := sample.Config()
+				c.Libraries[0].SkipRelease = true
+				return c
+			}(),
+			withChanges: []string{filepath.Join(sample.Lib1Output, "src", "lib.rs")},
+			wantVersion: sample.InitialVersion,
+		},
 		{
 			name: "library has changes but skipPublish is true",
 			cfg: func() *config.Config {
@@ -507,6 +557,7 @@ func TestDeriveNextVersion(t *testing.T) {
 		cfg             *config.Config
 		versionOpts     semver.DeriveNextOptions
 		versionOverride string
+		changes         semver.ChangeLevel
 		wantVersion     string
 	}{
 		{
@@ -518,6 +569,7 @@ func TestDeriveNextVersion(t *testing.T) {
 				return c
 			}(),
 			versionOpts: languageVersioningOptions[languageRust],
+			changes:     semver.Minor,
 			wantVersion: sample.RustNextNonGAVersion,
 		},
 		{
@@ -528,13 +580,27 @@ func TestDeriveNextVersion(t *testing.T) {
 				return c
 			}(),
 			versionOpts: languageVersioningOptions[languageRust],
+			changes:     semver.Minor,
 			wantVersion: sample.NextVersion,
 		},
 		{
 			name:        "default semver options next GA version",
 			cfg:         sample.Config(),
+			changes:     semver.Minor,
 			wantVersion: sample.NextVersion,
 		},
+		{
+			name:        "fix is a patch",
+			cfg:         sample.Config(),
+			changes:     semver.Patch,
+			wantVersion: "1.0.1",
+		},
+		{
+			name:        "breaking change is a major",
+			cfg:         sample.Config(),
+			changes:     semver.Major,
+			wantVersion: "2.0.0",
+		},
 		{
 			name: "version override, unreleased library",
 			cfg: func() *config.Config {
@@ -557,7 +623,7 @@ func TestDeriveNextVersion(t *testing.T) {
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := deriveNextVersion(t.Context(), "git", test.cfg, test.cfg.Libraries[0], test.versionOpts, test.versionOverride)
+			got, err := deriveNextVersion(t.Context(), "git", test.cfg, test.cfg.Libraries[0], test.versionOpts, test.versionOverride, test.changes)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -595,7 +661,7 @@ func TestDeriveNextVersion_Error(t *testing.T) {
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := deriveNextVersion(t.Context(), "git", test.cfg, test.cfg.Libraries[0], test.versionOpts, test.versionOverride)
+			got, err := deriveNextVersion(t.Context(), "git", test.cfg, test.cfg.Libraries[0], test.versionOpts, test.versionOverride, semver.Minor)
 			if err == nil {
 				t.Errorf("DeriveNextVersion() expected error; returned no error and version %s", got)
 			}
@@ -603,6 +669,151 @@ func TestDeriveNextVersion_Error(t *testing.T) {
 	}
 }
 
+func TestChangeLevel(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		messages []string
+		want     semver.ChangeLevel
+	}{
+		{
+			name: "no commits",
+			want: semver.Patch,
+		},
+		{
+			name:     "fix",
+			messages: []string{"fix: handle empty pages", "chore: update dependencies"},
+			want:     semver.Patch,
+		},
+		{
+			name:     "not conventional",
+			messages: []string{"Regenerate library"},
+			want:     semver.Patch,
+		},
+		{
+			name:     "feat",
+			messages: []string{"fix: handle empty pages", "feat(storage): add soft delete"},
+			want:     semver.Minor,
+		},
+		{
+			name:     "breaking marker",
+			messages: []string{"feat: add soft delete", "fix(storage)!: remove deprecated field"},
+			want:     semver.Major,
+		},
+		{
+			name:     "breaking footer",
+			messages: []string{"fix: rename field\n\nBREAKING CHANGE: the old field is removed"},
+			want:     semver.Major,
+		},
+		{
+			name:     "feat in body is ignored",
+			messages: []string{"fix: handle empty pages\n\nfeat: not a header"},
+			want:     semver.Patch,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := changeLevel(test.messages); got != test.want {
+				t.Errorf("changeLevel() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLibraryChangeLevel_Go(t *testing.T) {
+	testhelper.ContinueInNewGitRepository(t, t.TempDir())
+	commit := func(file, message string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(message), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "add", "."); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "commit", "-m", message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("README.md", "chore: initial version")
+	if err := command.Run(t.Context(), "git", "tag", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	commit("pubsub/apiv1/client.go", "feat!: remove deprecated method")
+	commit("secretmanager/apiv1/client.go", "fix: handle empty pages")
+
+	cfg := &config.Config{
+		Language: languageGo,
+		Default:  &config.Default{Output: "."},
+	}
+	for _, test := range []struct {
+		name string
+		want semver.ChangeLevel
+	}{
+		{name: "pubsub", want: semver.Major},
+		{name: "secretmanager", want: semver.Patch},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := libraryChangeLevel(t.Context(), cfg, &config.Library{Name: test.name}, "v1.0.0", "git")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("libraryChangeLevel() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLibraryChangeLevel_IgnoredChanges(t *testing.T) {
+	testhelper.ContinueInNewGitRepository(t, t.TempDir())
+	commit := func(file, message string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(message), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "add", "."); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "commit", "-m", message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("README.md", "chore: initial version")
+	if err := command.Run(t.Context(), "git", "tag", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	commit("storage/BUILD.bazel", "feat!: change build rules")
+	commit("storage/src/lib.rs", "fix: handle empty pages")
+
+	lib := &config.Library{Name: "storage", Output: "storage"}
+	for _, test := range []struct {
+		name           string
+		ignoredChanges []string
+		want           semver.ChangeLevel
+	}{
+		{name: "no ignored changes", want: semver.Major},
+		{name: "ignored build files", ignoredChanges: []string{"**/BUILD.bazel"}, want: semver.Patch},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Language: languageFake,
+				Release:  &config.Release{IgnoredChanges: test.ignoredChanges},
+			}
+			got, err := libraryChangeLevel(t.Context(), cfg, lib, "v1.0.0", "git")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("libraryChangeLevel() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
 func TestLoadBranchLibraryVersion(t *testing.T) {
 	testhelper.RequireCommand(t, "git")
 
@@ -623,6 +834,70 @@ func TestLoadBranchLibraryVersion(t *testing.T) {
 	}
 }
 
+func TestReadConfigAtRevision_LibrariesInclude(t *testing.T) {
+	testhelper.ContinueInNewGitRepository(t, t.TempDir())
+	commit := func(files map[string]string) {
+		t.Helper()
+		for name, content := range files {
+			if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := command.Run(t.Context(), "git", "add", "."); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "commit", "-m", "chore: update libraries"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit(map[string]string{
+		librarianConfigPath: "language: fake\nlibraries:\n  - name: bigquery\nlibraries_include:\n  - libs/*.yaml\n",
+		"libs/storage.yaml": "- name: storage\n  version: 1.0.0\n",
+		"libs/README.md":    "not a library file\n",
+	})
+	commit(map[string]string{
+		"libs/storage.yaml": "- name: storage\n  version: 1.1.0\n",
+	})
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*config.Library{
+		{Name: "bigquery"},
+		{Name: "storage", Version: "1.0.0"},
+	}
+	for _, test := range []struct {
+		name       string
+		dir        string
+		configFile string
+	}{
+		{
+			name:       "relative",
+			configFile: librarianConfigPath,
+		},
+		{
+			name:       "absolute from a subdirectory",
+			dir:        "libs",
+			configFile: filepath.Join(root, librarianConfigPath),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Chdir(filepath.Join(root, test.dir))
+			got, err := readConfigAtRevision(t.Context(), "git", "HEAD~", test.configFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got.Libraries); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestFindReleasedLibraries(t *testing.T) {
 	cfgBefore := &config.Config{
 		Libraries: []*config.Library{
@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+)
+
+// codeOwnersName is the name of the file, written to each library directory
+// and aggregated at the repository root, that assigns owners to libraries.
+const codeOwnersName = "CODEOWNERS"
+
+// codeOwnersHeader starts the aggregated codeOwnersName at the repository
+// root.
+const codeOwnersHeader = "# Code generated by librarian. DO NOT EDIT.\n"
+
+// writeCodeOwners writes codeOwnersName to the directory of lib, assigning
+// the directory to the owners of its APIs in cfg.Default.CodeOwners. Nothing
+// is written if none of the APIs has an owner, or if the library directory is
+// the repository root.
+func writeCodeOwners(cfg *config.Config, lib *config.Library) error {
+	owners := libraryOwners(cfg.Language, lib, cfg.Default.CodeOwners)
+	dir := filepath.Clean(libraryDir(cfg.Language, lib, cfg.Default))
+	if len(owners) == 0 || dir == "." {
+		return nil
+	}
+	content := fmt.Sprintf("/%s/ %s\n", filepath.ToSlash(dir), strings.Join(owners, " "))
+	return os.WriteFile(filepath.Join(dir, codeOwnersName), []byte(content), 0644)
+}
+
+// writeRootCodeOwners writes codeOwnersName at the repository root,
+// concatenating the fragments in the directories of all libraries in cfg, in
+// configuration order. Libraries without a fragment are skipped.
+func writeRootCodeOwners(cfg *config.Config) error {
+	var b strings.Builder
+	b.WriteString(codeOwnersHeader)
+	for _, lib := range cfg.Libraries {
+		dir := filepath.Clean(libraryDir(cfg.Language, lib, cfg.Default))
+		if dir == "." {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, codeOwnersName))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+	}
+	return os.WriteFile(codeOwnersName, []byte(b.String()), 0644)
+}
+
+// libraryOwners returns the sorted, distinct owners of the APIs of lib. The
+// owner of an API is the value in owners of the longest key that is the API
+// path or one of its parent directories.
+func libraryOwners(language string, lib *config.Library, owners map[string]string) []string {
+	var result []string
+	for _, path := range libraryAPIPaths(language, lib) {
+		var match string
+		for prefix := range owners {
+			if (path == prefix || strings.HasPrefix(path, prefix+"/")) && len(prefix) > len(match) {
+				match = prefix
+			}
+		}
+		if match != "" && !slices.Contains(result, owners[match]) {
+			result = append(result, owners[match])
+		}
+	}
+	slices.Sort(result)
+	return result
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+func TestGenerateCommand_CodeOwners(t *testing.T) {
+	googleapisDir := createGoogleapisServiceConfigs(t, t.TempDir(), map[string]string{
+		"google/cloud/speech/v1":       "speech_v1.yaml",
+		"google/cloud/texttospeech/v1": "texttospeech_v1.yaml",
+	})
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Default.CodeOwners = map[string]string{
+		"google/cloud":        "@googleapis/cloud",
+		"google/cloud/speech": "@googleapis/speech",
+	}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+		{
+			Name:   "library-two",
+			Output: "output2",
+			APIs:   []*config.API{{Path: "google/cloud/texttospeech/v1"}},
+		},
+	}
+	if err := yaml.Write(librarianConfigPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(t.Context(), "librarian", "generate", "--all"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		path string
+		want string
+	}{
+		{
+			path: filepath.Join("output1", codeOwnersName),
+			want: "/output1/ @googleapis/speech\n",
+		},
+		{
+			path: codeOwnersName,
+			want: codeOwnersHeader + "/output1/ @googleapis/speech\n/output2/ @googleapis/cloud\n",
+		},
+	} {
+		got, err := os.ReadFile(filepath.Join(tempDir, test.path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, string(got)); diff != "" {
+			t.Errorf("%s mismatch (-want +got):\n%s", test.path, diff)
+		}
+	}
+}
+
+func TestLibraryOwners(t *testing.T) {
+	owners := map[string]string{
+		"google/cloud":           "@cloud",
+		"google/cloud/speech":    "@speech",
+		"google/cloud/speechx":   "@speechx",
+		"google/cloud/translate": "@translate",
+	}
+	for _, test := range []struct {
+		name string
+		lib  *config.Library
+		want []string
+	}{
+		{
+			name: "longest prefix",
+			lib:  &config.Library{APIs: []*config.API{{Path: "google/cloud/speech/v1"}}},
+			want: []string{"@speech"},
+		},
+		{
+			name: "prefix matches whole directories",
+			lib:  &config.Library{APIs: []*config.API{{Path: "google/cloud/speechless/v1"}}},
+			want: []string{"@cloud"},
+		},
+		{
+			name: "several APIs",
+			lib: &config.Library{APIs: []*config.API{
+				{Path: "google/cloud/translate/v3"},
+				{Path: "google/cloud/speech/v1"},
+				{Path: "google/cloud/speech/v2"},
+			}},
+			want: []string{"@speech", "@translate"},
+		},
+		{
+			name: "no owner",
+			lib:  &config.Library{APIs: []*config.API{{Path: "google/ads/v1"}}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := libraryOwners(languageFake, test.lib, owners)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/serviceconfig"
+)
+
+// compatibilityMatrixName is the name of the file, written to each library
+// directory, that describes where and how the library's APIs are supported.
+const compatibilityMatrixName = "COMPATIBILITY.md"
+
+// writeCompatibilityMatrix writes compatibilityMatrixName to the directory of
+// lib. It lists, for each API of lib, the languages the API allowlist permits
+// to generate it and the transport the allowlist records for each language,
+// followed by the transport and release level of lib in the language of cfg.
+func writeCompatibilityMatrix(cfg *config.Config, lib *config.Library) error {
+	dir := libraryDir(cfg.Language, lib, cfg.Default)
+	content := compatibilityMatrix(cfg.Language, lib, serviceconfig.APIs)
+	return os.WriteFile(filepath.Join(dir, compatibilityMatrixName), []byte(content), 0644)
+}
+
+func compatibilityMatrix(language string, lib *config.Library, apis []serviceconfig.API) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s compatibility\n\n", lib.Name)
+	b.WriteString("| API | Languages | Transports |\n")
+	b.WriteString("| :--- | :--- | :--- |\n")
+	var transports []string
+	for _, path := range libraryAPIPaths(language, lib) {
+		api, ok := findAllowlistedAPI(apis, path)
+		if !ok {
+			fmt.Fprintf(&b, "| `%s` | not allowlisted | unspecified |\n", path)
+			continue
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s |\n", path, allowlistedLanguages(api), apiTransports(api))
+		if transport := api.Transports[language]; transport != "" && !slices.Contains(transports, transport) {
+			transports = append(transports, transport)
+		}
+	}
+	b.WriteString("\n| Language | Transport | Release level |\n")
+	b.WriteString("| :--- | :--- | :--- |\n")
+	fmt.Fprintf(&b, "| %s | %s | %s |\n", language, orUnspecified(strings.Join(transports, ", ")), orUnspecified(lib.ReleaseLevel))
+	return b.String()
+}
+
+// findAllowlistedAPI returns the entry of apis for path.
+func findAllowlistedAPI(apis []serviceconfig.API, path string) (serviceconfig.API, bool) {
+	for _, api := range apis {
+		if api.Path == path {
+			return api, true
+		}
+	}
+	return serviceconfig.API{}, false
+}
+
+// allowlistedLanguages describes the languages that api permits to generate
+// it.
+func allowlistedLanguages(api serviceconfig.API) string {
+	if len(api.Languages) == 0 {
+		return "all"
+	}
+	return strings.Join(api.Languages, ", ")
+}
+
+// apiTransports describes the transport of each language in api.Transports.
+func apiTransports(api serviceconfig.API) string {
+	var transports []string
+	for _, language := range slices.Sorted(maps.Keys(api.Transports)) {
+		transports = append(transports, fmt.Sprintf("%s: %s", language, api.Transports[language]))
+	}
+	return orUnspecified(strings.Join(transports, ", "))
+}
+
+func orUnspecified(s string) string {
+	if s == "" {
+		return "unspecified"
+	}
+	return s
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/serviceconfig"
+)
+
+func TestCompatibilityMatrix(t *testing.T) {
+	apis := []serviceconfig.API{
+		{
+			Path:       "google/cloud/batch/v1",
+			Languages:  []string{languagePython},
+			Transports: map[string]string{languagePython: "grpc+rest"},
+		},
+		{
+			Path:       "google/cloud/asset/v1",
+			Transports: map[string]string{languageGo: "grpc", languagePython: "grpc"},
+		},
+	}
+	lib := &config.Library{
+		Name:         "google-cloud-batch",
+		ReleaseLevel: "preview",
+		APIs: []*config.API{
+			{Path: "google/cloud/batch/v1"},
+			{Path: "google/cloud/asset/v1"},
+			{Path: "google/cloud/unknown/v1"},
+		},
+	}
+	got := compatibilityMatrix(languagePython, lib, apis)
+	want := `# google-cloud-batch compatibility
+
+| API | Languages | Transports |
+| :--- | :--- | :--- |
+| ` + "`google/cloud/batch/v1`" + ` | python | python: grpc+rest |
+| ` + "`google/cloud/asset/v1`" + ` | all | go: grpc, python: grpc |
+| ` + "`google/cloud/unknown/v1`" + ` | not allowlisted | unspecified |
+
+| Language | Transport | Release level |
+| :--- | :--- | :--- |
+| python | grpc+rest, grpc | preview |
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteCompatibilityMatrix(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cfg := &config.Config{
+		Language: languageGo,
+		Default:  &config.Default{Output: "."},
+	}
+	lib := &config.Library{
+		Name:   "accessapproval",
+		Output: ".",
+		APIs:   []*config.API{{Path: "google/cloud/accessapproval/v1"}},
+	}
+	// The Go library shares the output root, so the matrix is written to
+	// the library's own directory.
+	dir := libraryDir(cfg.Language, lib, cfg.Default)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeCompatibilityMatrix(cfg, lib); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, compatibilityMatrixName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# accessapproval compatibility
+
+| API | Languages | Transports |
+| :--- | :--- | :--- |
+| ` + "`google/cloud/accessapproval/v1`" + ` | all | unspecified |
+
+| Language | Transport | Release level |
+| :--- | :--- | :--- |
+| go | unspecified | unspecified |
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -95,6 +95,51 @@ func TestGenerate(t *testing.T) {
 	}
 }
 
+func TestGenerate_IncludeList(t *testing.T) {
+	testhelper.RequireCommand(t, "protoc")
+	testhelper.RequireCommand(t, "dart")
+
+	googleapisDir, err := filepath.Abs("../../testdata/googleapis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outDir := t.TempDir()
+
+	library := &config.Library{
+		Name:          "google-cloud-secretmanager-v1",
+		Version:       "0.1.0",
+		Output:        outDir,
+		CopyrightYear: "2025",
+		APIs: []*config.API{
+			{
+				Path: "google/cloud/secretmanager/v1",
+			},
+		},
+		Dart: &config.DartPackage{
+			IncludeList: []string{"resources.proto"},
+			Packages: map[string]string{
+				"package:google_cloud_protobuf": "^0.4.0",
+			},
+		},
+	}
+	if err := Generate(t.Context(), library, googleapisDir); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(outDir, "lib", "secretmanager.dart"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Secret is defined in resources.proto, which is included.
+	if !strings.Contains(string(got), "class Secret ") {
+		t.Errorf("generated library is missing message from included resources.proto")
+	}
+	// SecretManagerService is defined in service.proto, which is excluded.
+	if strings.Contains(string(got), "SecretManagerService") {
+		t.Errorf("generated library contains service from excluded service.proto")
+	}
+}
+
 func TestFormat(t *testing.T) {
 	testhelper.RequireCommand(t, "dart")
 	outDir := t.TempDir()
@@ -35,7 +35,7 @@ func TestGenerate(t *testing.T) {
 
 	tmpDir := t.TempDir()
 	t.Chdir(tmpDir)
-	if err := generate(t.Context(), "fake", library, "", nil); err != nil {
+	if err := generate(t.Context(), "fake", library, "", nil, generateOptions{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -0,0 +1,306 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/googleapis/librarian/internal/command"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/fetch"
+)
+
+// formatCacheMaxSize is the size, in bytes, above which prune removes the
+// least recently used entries of a formatCache.
+const formatCacheMaxSize = 1 << 30
+
+// formatterConfigFiles lists, by language, the files that configure the
+// formatters. They are looked up in the work root and in each library output,
+// and their content is part of the cache key, so that changing the formatter
+// configuration invalidates cached results.
+var formatterConfigFiles = map[string][]string{
+	languageDart: {"analysis_options.yaml"},
+	languageRust: {"rustfmt.toml", ".rustfmt.toml", "taplo.toml", ".taplo.toml", "rust-toolchain.toml"},
+}
+
+// formatCache stores the result of formatting files, keyed by the SHA256 of
+// the unformatted content, the formatter version and the formatter
+// configuration. When every file in a library output has been formatted
+// before, the cached results are restored instead of running the formatter
+// again.
+//
+// Cache structure:
+//
+//	$LIBRARIAN_CACHE/format/
+//	└── $sha256        # Formatted content of a file
+type formatCache struct {
+	dir         string
+	version     string
+	configFiles []string
+	// maxSize is the size prune shrinks the cache to.
+	maxSize int64
+	// start is when the cache was opened. Entries used since then have a
+	// later modification time.
+	start time.Time
+}
+
+// newFormatCache returns a formatCache for the formatter of the given
+// language, stored in the librarian cache directory. It returns nil if the
+// language has no separate formatting step.
+func newFormatCache(ctx context.Context, language string) (*formatCache, error) {
+	version, err := formatterVersion(ctx, language)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		return nil, nil
+	}
+	cacheDir, err := fetch.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &formatCache{
+		dir:         filepath.Join(cacheDir, "format"),
+		version:     version,
+		configFiles: formatterConfigFiles[language],
+		maxSize:     formatCacheMaxSize,
+		start:       time.Now(),
+	}, nil
+}
+
+// formatterVersion returns a string identifying the formatters used for
+// language, so that cached results are invalidated when a formatter changes.
+// The formatters are asked for their version, once per run, because the
+// binaries on the PATH are often toolchain proxies, such as those installed
+// by rustup, whose identity does not change when the formatter does.
+func formatterVersion(ctx context.Context, language string) (string, error) {
+	var versions []string
+	switch language {
+	case languageFake, languageGo, languagePython:
+		// These languages have no separately versioned formatter.
+		return "", nil
+	case languageDart:
+		v, err := command.Output(ctx, "dart", "--version")
+		if err != nil {
+			return "", err
+		}
+		versions = append(versions, v)
+	case languageRust:
+		for _, args := range [][]string{{"taplo", "--version"}, {"cargo", "fmt", "--version"}} {
+			v, err := command.Output(ctx, args[0], args[1:]...)
+			if err != nil {
+				return "", err
+			}
+			versions = append(versions, v)
+		}
+	default:
+		return "", fmt.Errorf("language %q does not support formatting", language)
+	}
+	return strings.Join(versions, ""), nil
+}
+
+// format formats library.Output using formatLibrary. If every file in
+// library.Output is found in the cache, the cached results are written
+// instead and formatLibrary is not called. A nil cache always calls
+// formatLibrary.
+func (c *formatCache) format(library *config.Library, formatLibrary func() error) error {
+	if c == nil {
+		return formatLibrary()
+	}
+	salt, err := c.salt(library.Output)
+	if err != nil {
+		return err
+	}
+	keys, err := c.keys(library.Output, salt)
+	if err != nil {
+		return err
+	}
+	cached, err := c.lookup(keys)
+	if err != nil {
+		return err
+	}
+	if cached != nil {
+		slog.Debug("format cache hit", "library", library.Name)
+		for path, content := range cached {
+			if err := writeFilePreservingMode(path, content); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := formatLibrary(); err != nil {
+		return err
+	}
+	return c.store(keys)
+}
+
+// salt returns the formatter version followed by the content of every
+// formatter configuration file found in the work root or in dir.
+func (c *formatCache) salt(dir string) ([]byte, error) {
+	salt := []byte(c.version)
+	for _, root := range []string{".", dir} {
+		for _, name := range c.configFiles {
+			content, err := os.ReadFile(filepath.Join(root, name))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			salt = fmt.Appendf(salt, "\x00%s\x00%d\x00", filepath.Join(root, name), len(content))
+			salt = append(salt, content...)
+		}
+	}
+	return salt, nil
+}
+
+// keys returns the cache key for the current content of every file in dir,
+// indexed by path.
+func (c *formatCache) keys(dir string, salt []byte) (map[string]string, error) {
+	keys := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		keys[path] = formatCacheKey(salt, content)
+		return nil
+	})
+	return keys, err
+}
+
+func formatCacheKey(salt, content []byte) string {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte{0})
+	h.Write(content)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// writeFilePreservingMode replaces the content of the existing file at path,
+// keeping its permissions.
+func writeFilePreservingMode(path string, content []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, info.Mode().Perm())
+}
+
+// lookup returns the cached formatted content for every path in keys. It
+// returns nil if any path is missing from the cache. The entries found are
+// marked as used, so that prune keeps them.
+func (c *formatCache) lookup(keys map[string]string) (map[string][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	cached := make(map[string][]byte)
+	for path, key := range keys {
+		content, err := os.ReadFile(filepath.Join(c.dir, key))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		cached[path] = content
+	}
+	now := time.Now()
+	for _, key := range keys {
+		if err := os.Chtimes(filepath.Join(c.dir, key), now, now); err != nil {
+			return nil, err
+		}
+	}
+	return cached, nil
+}
+
+// store records the current content of every path in keys, which is expected
+// to be formatted, under the key of its unformatted content. Paths removed by
+// the formatter are ignored.
+func (c *formatCache) store(keys map[string]string) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	for path, key := range keys {
+		content, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(c.dir, key), content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prune removes the least recently used entries until the cache is no larger
+// than c.maxSize. Entries used since the cache was opened are kept. A nil
+// cache does nothing.
+func (c *formatCache) prune() error {
+	if c == nil {
+		return nil
+	}
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var (
+		infos []fs.FileInfo
+		size  int64
+	)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		infos = append(infos, info)
+		size += info.Size()
+	}
+	slices.SortFunc(infos, func(a, b fs.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+	for _, info := range infos {
+		if size <= c.maxSize || !info.ModTime().Before(c.start) {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, info.Name())); err != nil {
+			return err
+		}
+		size -= info.Size()
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+)
+
+func TestFormatCache(t *testing.T) {
+	library := &config.Library{Name: "test", Output: t.TempDir()}
+	readme := filepath.Join(library.Output, "README.md")
+	cache := &formatCache{dir: t.TempDir(), version: "v1"}
+	calls := 0
+	format := func() error {
+		calls++
+		return fakeFormat(library)
+	}
+	for _, want := range []struct {
+		content string
+		calls   int
+	}{
+		{"unformatted", 1},
+		// Regenerating the same content restores the cached result.
+		{"unformatted", 1},
+		{"changed", 2},
+	} {
+		if err := os.WriteFile(readme, []byte(want.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.format(library, format); err != nil {
+			t.Fatal(err)
+		}
+		if calls != want.calls {
+			t.Errorf("format called %d times, want %d", calls, want.calls)
+		}
+		got, err := os.ReadFile(readme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want.content+"\n---\nFormatted\n", string(got)); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestFormatCache_VersionChange(t *testing.T) {
+	library := &config.Library{Name: "test", Output: t.TempDir()}
+	readme := filepath.Join(library.Output, "README.md")
+	dir := t.TempDir()
+	calls := 0
+	format := func() error {
+		calls++
+		return fakeFormat(library)
+	}
+	for _, version := range []string{"v1", "v2"} {
+		if err := os.WriteFile(readme, []byte("unformatted"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cache := &formatCache{dir: dir, version: version}
+		if err := cache.format(library, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("format called %d times, want 2", calls)
+	}
+}
+
+func TestFormatCache_ConfigChange(t *testing.T) {
+	t.Chdir(t.TempDir())
+	library := &config.Library{Name: "test", Output: "output"}
+	if err := os.MkdirAll(library.Output, 0755); err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(library.Output, "README.md")
+	cache := &formatCache{dir: t.TempDir(), version: "v1", configFiles: []string{"rustfmt.toml"}}
+	calls := 0
+	format := func() error {
+		calls++
+		return fakeFormat(library)
+	}
+	for _, config := range []string{"max_width = 100\n", "max_width = 100\n", "max_width = 80\n"} {
+		if err := os.WriteFile("rustfmt.toml", []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(readme, []byte("unformatted"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.format(library, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("format called %d times, want 2", calls)
+	}
+}
+
+func TestFormatCache_PreservesMode(t *testing.T) {
+	library := &config.Library{Name: "test", Output: t.TempDir()}
+	script := filepath.Join(library.Output, "run.sh")
+	cache := &formatCache{dir: t.TempDir(), version: "v1"}
+	format := func() error { return fakeFormat(library) }
+	for range 2 {
+		if err := os.WriteFile(filepath.Join(library.Output, "README.md"), []byte("unformatted"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(script, []byte("unformatted"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(script, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.format(library, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info, err := os.Stat(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0755 {
+		t.Errorf("got mode %v, want %v", got, os.FileMode(0755))
+	}
+}
+
+func TestFormatCache_Prune(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now()
+	for i, entry := range []struct {
+		name string
+		age  time.Duration
+	}{
+		{"oldest", 3 * time.Hour},
+		{"older", 2 * time.Hour},
+		{"old", time.Hour},
+		// Used by the current run.
+		{"used", -time.Minute},
+	} {
+		path := filepath.Join(dir, entry.name)
+		if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := start.Add(-entry.age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+	}
+	cache := &formatCache{dir: dir, maxSize: 25, start: start}
+	if err := cache.prune(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	want := []string{"old", "used"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFormatCache_PruneKeepsUsed(t *testing.T) {
+	library := &config.Library{Name: "test", Output: t.TempDir()}
+	if err := os.WriteFile(filepath.Join(library.Output, "README.md"), []byte("unformatted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cache := &formatCache{dir: t.TempDir(), version: "v1", start: time.Now().Add(-time.Second)}
+	if err := cache.format(library, func() error { return fakeFormat(library) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.prune(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(cache.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d entries, want the 1 entry stored by this run", len(entries))
+	}
+}
+
+func TestFormatCache_Nil(t *testing.T) {
+	var cache *formatCache
+	called := false
+	if err := cache.format(&config.Library{}, func() error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Error("format was not called")
+	}
+	if err := cache.prune(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -15,19 +15,26 @@
 package librarian
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
 	"github.com/googleapis/librarian/internal/librarian/dart"
 	"github.com/googleapis/librarian/internal/librarian/golang"
 	"github.com/googleapis/librarian/internal/librarian/python"
 	"github.com/googleapis/librarian/internal/librarian/rust"
+	"github.com/googleapis/librarian/internal/serviceconfig"
 	"github.com/urfave/cli/v3"
 	"golang.org/x/sync/errgroup"
 )
@@ -39,50 +46,158 @@ const (
 	showcaseRepo   = "github.com/googleapis/gapic-showcase"
 )
 
+// defaultPreservedFiles lists the files at the root of a library output
+// directory that cleanOutput never removes, unless overridden by
+// Default.PreservedFiles.
+var defaultPreservedFiles = []string{".gitattributes", ".gitignore", "CODEOWNERS", "OWNERS"}
+
 var (
 	errMissingLibraryOrAllFlag = errors.New("must specify library name or use --all flag")
 	errBothLibraryAndAllFlag   = errors.New("cannot specify both library name and --all flag")
 	errEmptySources            = errors.New("sources required in librarian.yaml")
 	errSkipGenerate            = errors.New("library has skip_generate set")
+	errLibrariesFromConflict   = errors.New("cannot combine --libraries-from with library name or --all flag")
+	errEmptyLibrariesFrom      = errors.New("no library names given to --libraries-from")
 )
 
 func generateCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "generate",
 		Usage:     "generate a client library",
-		UsageText: "librarian generate [library] [--all]",
+		UsageText: "librarian generate [library] [--all] [--libraries-from file] [--force-generate]",
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "all",
 				Usage: "generate all libraries",
 			},
+			&cli.StringFlag{
+				Name:  "libraries-from",
+				Usage: "generate the libraries named in this file, one per line; use - to read from stdin",
+			},
+			&cli.BoolFlag{
+				Name:  "force-generate",
+				Usage: "generate the named libraries even if they have skip_generate set; has no effect with --all",
+			},
+			&cli.BoolFlag{
+				Name:  "no-format",
+				Usage: "skip formatting the generated code; Python code is formatted by its post processor and is always formatted",
+			},
+			&cli.BoolFlag{
+				Name:  "no-clean",
+				Usage: "generate on top of the existing output without cleaning it first; files the generator no longer produces are left behind",
+			},
+			&cli.StringFlag{
+				Name:  "cpuprofile",
+				Usage: "write a pprof CPU profile of the run to this file",
+			},
+			&cli.StringFlag{
+				Name:  "memprofile",
+				Usage: "write a pprof memory profile at the end of the run to this file",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			all := cmd.Bool("all")
 			libraryName := cmd.Args().First()
-			if !all && libraryName == "" {
+			librariesFrom := cmd.String("libraries-from")
+			if librariesFrom != "" && (all || libraryName != "") {
+				return errLibrariesFromConflict
+			}
+			if !all && libraryName == "" && librariesFrom == "" {
 				return errMissingLibraryOrAllFlag
 			}
 			if all && libraryName != "" {
 				return errBothLibraryAndAllFlag
 			}
+			libraryNames := []string{libraryName}
+			if librariesFrom != "" {
+				names, err := readLibraryNames(cmd.Root().Reader, librariesFrom)
+				if err != nil {
+					return err
+				}
+				libraryNames = names
+			}
 			cfg, err := loadConfig(ctx)
 			if err != nil {
 				return err
 			}
-			return runGenerate(ctx, cfg, all, libraryName)
+			stopProfiles, err := startProfiles(cmd.String("cpuprofile"), cmd.String("memprofile"))
+			if err != nil {
+				return err
+			}
+			err = runGenerate(ctx, cfg, all, libraryNames, generateOptions{
+				force:      cmd.Bool("force-generate"),
+				skipFormat: cmd.Bool("no-format"),
+				skipClean:  cmd.Bool("no-clean"),
+			})
+			return errors.Join(err, stopProfiles())
 		},
 	}
 }
 
-func runGenerate(ctx context.Context, cfg *config.Config, all bool, libraryName string) error {
+// readLibraryNames reads newline-separated library names from the file at
+// path, or from stdin if path is "-". Blank lines and repeated names are
+// ignored.
+func readLibraryNames(stdin io.Reader, path string) ([]string, error) {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var names []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errEmptyLibrariesFrom
+	}
+	return names, nil
+}
+
+// generateOptions holds settings that change how libraries are generated.
+// The zero value generates normally.
+type generateOptions struct {
+	// force generates libraries named explicitly even if they have
+	// skip_generate set. Libraries with skip_generate set are still skipped
+	// when generating all libraries.
+	force bool
+	// skipFormat leaves the generated code unformatted.
+	skipFormat bool
+	// skipClean generates on top of the existing output instead of cleaning
+	// it first. Files that the generator no longer produces are not removed,
+	// so the output may contain stale files.
+	skipClean bool
+}
+
+// runGenerate generates the libraries named in libraryNames, or every library
+// if all is set.
+func runGenerate(ctx context.Context, cfg *config.Config, all bool, libraryNames []string, opts generateOptions) error {
 	if cfg.Sources == nil {
 		return errEmptySources
 	}
-	return generateLibraries(ctx, all, cfg, libraryName)
+	return generateLibraries(ctx, all, cfg, libraryNames, opts)
 }
 
-func generateLibraries(ctx context.Context, all bool, cfg *config.Config, libraryName string) error {
+func generateLibraries(ctx context.Context, all bool, cfg *config.Config, libraryNames []string, opts generateOptions) error {
+	if !all {
+		if err := checkLibraryNames(cfg, libraryNames, opts.force); err != nil {
+			return err
+		}
+	}
+
 	// Fetch sources.
 	googleapisDir, err := fetchSource(ctx, cfg.Sources.Googleapis, googleapisRepo)
 	if err != nil {
@@ -101,54 +216,115 @@ func generateLibraries(ctx context.Context, all bool, cfg *config.Config, librar
 	// This avoids race conditions when output directories are nested.
 	var libraries []*config.Library
 	for _, lib := range cfg.Libraries {
-		if !shouldGenerate(lib, all, libraryName) {
+		if !shouldGenerate(lib, all, opts.force, libraryNames) {
 			continue
 		}
-		prepared, err := prepareLibrary(cfg.Language, lib, cfg.Default)
+		if lib.RequirePublishing {
+			ok, err := hasPublishing(cfg.Language, lib, googleapisDir)
+			if err != nil {
+				return fmt.Errorf("library %q: %w", lib.Name, err)
+			}
+			if !ok {
+				slog.Info("skipping library: service config has no publishing section", "library", lib.Name)
+				continue
+			}
+		}
+		if err := runHooks(ctx, cfg, hookPreClean, lib); err != nil {
+			return err
+		}
+		prepared, err := prepareLibrary(cfg.Language, lib, cfg.Default, opts.skipClean)
 		if err != nil {
 			return err
 		}
 		libraries = append(libraries, prepared)
 	}
 	if len(libraries) == 0 {
-		if all {
-			return errors.New("no libraries to generate: all libraries have skip_generate set")
-		}
-		for _, lib := range cfg.Libraries {
-			if lib.Name == libraryName {
-				return fmt.Errorf("%w: %q", errSkipGenerate, libraryName)
-			}
-		}
-		return fmt.Errorf("%w: %q", ErrLibraryNotFound, libraryName)
+		return errors.New("no libraries to generate: all libraries were skipped")
 	}
 
 	// Generate all libraries in parallel.
+	progress := newGenerateProgress(len(libraries), command.Verbose)
 	g, gctx := errgroup.WithContext(ctx)
 	for _, lib := range libraries {
 		lib := lib
 		g.Go(func() error {
-			return generate(gctx, cfg.Language, lib, googleapisDir, rustSources)
+			return progress.track(lib.Name, func() error {
+				return generate(gctx, cfg.Language, lib, googleapisDir, rustSources, opts)
+			})
 		})
 	}
-	if err := g.Wait(); err != nil {
+	err = g.Wait()
+	progress.done()
+	if err != nil {
 		return err
 	}
 
-	// Format all libraries sequentially.
+	if err := runHooks(ctx, cfg, hookPostGenerate, libraries...); err != nil {
+		return err
+	}
+	if !opts.skipFormat {
+		if err := formatLibraries(ctx, cfg.Language, libraries); err != nil {
+			return err
+		}
+		if err := runHooks(ctx, cfg, hookPostFormat, libraries...); err != nil {
+			return err
+		}
+	}
+	if cfg.Default != nil && cfg.Default.CompatibilityMatrix {
+		for _, lib := range libraries {
+			if err := writeCompatibilityMatrix(cfg, lib); err != nil {
+				return err
+			}
+		}
+	}
+	if cfg.Default != nil && cfg.Default.FileManifest {
+		for _, lib := range libraries {
+			if err := writeFileManifest(cfg, lib); err != nil {
+				return err
+			}
+		}
+	}
+	if cfg.Default != nil && len(cfg.Default.CodeOwners) > 0 {
+		for _, lib := range libraries {
+			if err := writeCodeOwners(cfg, lib); err != nil {
+				return err
+			}
+		}
+		if err := writeRootCodeOwners(cfg); err != nil {
+			return err
+		}
+	}
+	return postGenerate(ctx, cfg.Language, libraries)
+}
+
+// formatLibraries formats all libraries sequentially, skipping those whose
+// files are unchanged since they were last formatted, and then prunes the
+// format cache.
+func formatLibraries(ctx context.Context, language string, libraries []*config.Library) error {
+	cache, err := newFormatCache(ctx, language)
+	if err != nil {
+		return err
+	}
 	for _, lib := range libraries {
-		if err := formatLibrary(ctx, cfg.Language, lib); err != nil {
+		if err := cache.format(lib, func() error {
+			return formatLibrary(ctx, language, lib)
+		}); err != nil {
 			return err
 		}
 	}
-	return postGenerate(ctx, cfg.Language)
+	return cache.prune()
 }
 
 // postGenerate performs repository-level actions after all individual
 // libraries have been generated.
-func postGenerate(ctx context.Context, language string) error {
+func postGenerate(ctx context.Context, language string, libraries []*config.Library) error {
 	switch language {
 	case languageRust:
-		return rust.UpdateWorkspace(ctx)
+		var crates []string
+		for _, lib := range libraries {
+			crates = append(crates, lib.Output)
+		}
+		return rust.UpdateWorkspace(ctx, crates)
 	case languageFake:
 		return fakePostGenerate()
 	default:
@@ -169,6 +345,8 @@ func defaultOutput(language, name, api, defaultOut string) string {
 
 func deriveAPIPath(language, name string) string {
 	switch language {
+	case languagePython:
+		return python.DeriveAPIPath(name)
 	case languageRust:
 		return rust.DeriveAPIPath(name)
 	default:
@@ -176,24 +354,85 @@ func deriveAPIPath(language, name string) string {
 	}
 }
 
-func shouldGenerate(lib *config.Library, all bool, libraryName string) bool {
-	if lib.SkipGenerate {
+// shouldGenerate reports whether lib is selected for generation, either by
+// all or by being named in libraryNames. Libraries with skip_generate set are
+// not selected, unless force is set and they are named.
+func shouldGenerate(lib *config.Library, all, force bool, libraryNames []string) bool {
+	if lib.SkipGenerate && (all || !force) {
 		return false
 	}
-	return all || lib.Name == libraryName
+	return all || slices.Contains(libraryNames, lib.Name)
+}
+
+// checkLibraryNames returns an error if any of libraryNames is missing from
+// cfg or, unless force is set, has skip_generate set.
+func checkLibraryNames(cfg *config.Config, libraryNames []string, force bool) error {
+	for _, name := range libraryNames {
+		i := slices.IndexFunc(cfg.Libraries, func(lib *config.Library) bool { return lib.Name == name })
+		if i == -1 {
+			if suggestion := closestLibraryName(cfg, name); suggestion != "" {
+				return fmt.Errorf("%w: %q, did you mean %q?", ErrLibraryNotFound, name, suggestion)
+			}
+			return fmt.Errorf("%w: %q", ErrLibraryNotFound, name)
+		}
+		if cfg.Libraries[i].SkipGenerate && !force {
+			return fmt.Errorf("%w: %q", errSkipGenerate, name)
+		}
+	}
+	return nil
+}
+
+// closestLibraryName returns the name of the library in cfg closest to name
+// by Levenshtein distance, or an empty string if none is within a third of
+// the length of name. Ties go to the library listed first.
+func closestLibraryName(cfg *config.Config, name string) string {
+	best, bestDistance := "", max(1, len(name)/3)+1
+	for _, lib := range cfg.Libraries {
+		if d := levenshtein(name, lib.Name); d < bestDistance {
+			best, bestDistance = lib.Name, d
+		}
+	}
+	return best
 }
 
-// prepareLibrary applies defaults and cleans the output directory.
-func prepareLibrary(language string, lib *config.Library, defaults *config.Default) (*config.Library, error) {
+// levenshtein returns the number of single-byte insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// prepareLibrary applies defaults and, unless skipClean is set, cleans the
+// output directory.
+func prepareLibrary(language string, lib *config.Library, defaults *config.Default, skipClean bool) (*config.Library, error) {
 	library, err := applyDefaults(language, lib, defaults)
 	if err != nil {
 		return nil, err
 	}
+	if skipClean {
+		return library, nil
+	}
+	preserved := preservedFiles(defaults)
 	switch language {
 	case languageFake:
 		// No cleaning needed.
 	case languageDart, languageGo, languagePython:
-		if err := cleanOutput(library.Output, library.Keep); err != nil {
+		if err := cleanOutput(library.Output, library.Keep, preserved); err != nil {
 			return nil, err
 		}
 	case languageRust:
@@ -201,14 +440,36 @@ func prepareLibrary(language string, lib *config.Library, defaults *config.Defau
 		if err != nil {
 			return nil, fmt.Errorf("library %q: %w", library.Name, err)
 		}
-		if err := cleanOutput(library.Output, keep); err != nil {
+		if err := cleanOutput(library.Output, keep, preserved); err != nil {
 			return nil, err
 		}
 	}
 	return library, nil
 }
 
-func generate(ctx context.Context, language string, library *config.Library, googleapisDir string, rustSources *rust.Sources) error {
+// hasPublishing reports whether the service config of every API in lib has a
+// publishing section. An API without a service config has none.
+func hasPublishing(language string, lib *config.Library, googleapisDir string) (bool, error) {
+	for _, path := range libraryAPIPaths(language, lib) {
+		api, err := serviceconfig.Find(googleapisDir, path)
+		if err != nil {
+			return false, err
+		}
+		if api.ServiceConfig == "" {
+			return false, nil
+		}
+		svc, err := serviceconfig.Read(filepath.Join(googleapisDir, api.ServiceConfig))
+		if err != nil {
+			return false, err
+		}
+		if svc.GetPublishing() == nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func generate(ctx context.Context, language string, library *config.Library, googleapisDir string, rustSources *rust.Sources, opts generateOptions) error {
 	switch language {
 	case languageFake:
 		if err := fakeGenerate(library); err != nil {
@@ -226,6 +487,12 @@ func generate(ctx context.Context, language string, library *config.Library, goo
 		if err := golang.Generate(ctx, library, googleapisDir); err != nil {
 			return err
 		}
+		if opts.skipFormat {
+			return nil
+		}
+		if err := golang.TidyImports(library); err != nil {
+			return err
+		}
 	case languageRust:
 		if err := rust.Generate(ctx, library, rustSources); err != nil {
 			return err
@@ -251,32 +518,15 @@ func fetchRustSources(ctx context.Context, cfgSources *config.Sources) (*rust.So
 		return nil
 	})
 	g.Go(func() error {
-		dir, err := fetchSource(ctx, cfgSources.Conformance, protobufRepo)
+		include, err := fetchIncludeSources(ctx, cfgSources)
 		if err != nil {
 			return err
 		}
-		sources.Conformance = dir
+		sources.Conformance = include.Conformance
+		sources.ProtobufSrc = include.ProtobufSrc
+		sources.Showcase = include.Showcase
 		return nil
 	})
-	g.Go(func() error {
-		dir, err := fetchSource(ctx, cfgSources.Showcase, showcaseRepo)
-		if err != nil {
-			return err
-		}
-		sources.Showcase = dir
-		return nil
-	})
-
-	if cfgSources.ProtobufSrc != nil {
-		g.Go(func() error {
-			dir, err := fetchSource(ctx, cfgSources.ProtobufSrc, protobufRepo)
-			if err != nil {
-				return err
-			}
-			sources.ProtobufSrc = filepath.Join(dir, cfgSources.ProtobufSrc.Subpath)
-			return nil
-		})
-	}
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
@@ -293,6 +543,7 @@ func formatLibrary(ctx context.Context, language string, library *config.Library
 		return rust.Format(ctx, library)
 	case languageGo, languagePython:
 		// Go and Python formatting is currently performed in the generate phase.
+		// For Go, generate skips it when generateOptions.skipFormat is set.
 		// TODO(https://github.com/googleapis/librarian/issues/3730): separate
 		// generation and formatting for Python.
 		return nil
@@ -300,10 +551,23 @@ func formatLibrary(ctx context.Context, language string, library *config.Library
 	return fmt.Errorf("language %q does not support formatting", language)
 }
 
+// preservedFiles returns the files at the root of each library output
+// directory that must survive cleaning.
+func preservedFiles(defaults *config.Default) []string {
+	if defaults == nil || defaults.PreservedFiles == nil {
+		return defaultPreservedFiles
+	}
+	return defaults.PreservedFiles
+}
+
 // cleanOutput removes all files in dir except those in keep. The keep list
-// should contain paths relative to dir. It returns an error if any file
-// in keep does not exist.
-func cleanOutput(dir string, keep []string) error {
+// should contain paths relative to dir; a directory in keep is kept along with
+// everything inside it. Entries containing wildcards are
+// treated as glob patterns, where "**" matches any number of directories. It
+// returns an error if any file in keep does not exist, or if any pattern in
+// keep matches nothing. Files directly in dir whose names are in preserved
+// are also kept, but unlike keep, they need not exist.
+func cleanOutput(dir string, keep, preserved []string) error {
 	info, err := os.Stat(dir)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
@@ -317,6 +581,19 @@ func cleanOutput(dir string, keep []string) error {
 
 	keepSet := make(map[string]bool)
 	for _, k := range keep {
+		if isKeepPattern(k) {
+			matches, err := globKeep(dir, k)
+			if err != nil {
+				return err
+			}
+			if len(matches) == 0 {
+				return fmt.Errorf("keep pattern %q matches no files", k)
+			}
+			for _, m := range matches {
+				keepSet[m] = true
+			}
+			continue
+		}
 		path := filepath.Join(dir, k)
 		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
 			return fmt.Errorf("keep file %q does not exist", k)
@@ -331,20 +608,102 @@ func cleanOutput(dir string, keep []string) error {
 		}
 		keepSet[rel] = true
 	}
+	for _, name := range preserved {
+		keepSet[name] = true
+	}
 	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
 		if err != nil {
 			return err
 		}
 		if d.IsDir() {
+			// Directories are never removed. A kept directory also keeps
+			// everything inside it.
+			if keepSet[rel] {
+				return filepath.SkipDir
+			}
 			return nil
 		}
+		if keepSet[rel] {
+			return nil
+		}
+		return os.Remove(path)
+	})
+}
+
+// isKeepPattern reports whether a keep entry is a glob pattern rather than a
+// literal path.
+func isKeepPattern(k string) bool {
+	return strings.ContainsAny(k, "*?[")
+}
+
+// globKeep returns the paths, relative to dir, of all files and directories
+// matching pattern.
+func globKeep(dir, pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid keep pattern %q: %w", pattern, err)
+		}
+		var rels []string
+		for _, m := range matches {
+			rel, err := filepath.Rel(dir, m)
+			if err != nil {
+				return nil, err
+			}
+			rels = append(rels, rel)
+		}
+		return rels, nil
+	}
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/")
+	var rels []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
 		rel, err := filepath.Rel(dir, path)
 		if err != nil {
 			return err
 		}
-		if keepSet[rel] {
+		if rel == "." {
 			return nil
 		}
-		return os.Remove(path)
+		ok, err := matchSegments(segments, strings.Split(filepath.ToSlash(rel), "/"))
+		if err != nil {
+			return fmt.Errorf("invalid keep pattern %q: %w", pattern, err)
+		}
+		if ok {
+			rels = append(rels, rel)
+		}
+		return nil
 	})
+	return rels, err
+}
+
+// matchSegments reports whether the path segments in name match the pattern
+// segments, where a "**" segment matches zero or more path segments.
+func matchSegments(pattern, name []string) (bool, error) {
+	if len(pattern) == 0 {
+		return len(name) == 0, nil
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			ok, err := matchSegments(pattern[1:], name[i:])
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+	if len(name) == 0 {
+		return false, nil
+	}
+	ok, err := path.Match(pattern[0], name[0])
+	if err != nil || !ok {
+		return false, err
+	}
+	return matchSegments(pattern[1:], name[1:])
 }
@@ -17,9 +17,11 @@ package librarian
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -219,6 +221,16 @@ func TestGenerateSkip(t *testing.T) {
 			args:    []string{"librarian", "generate", lib1},
 			wantErr: errSkipGenerate,
 		},
+		{
+			name: "force with library name",
+			args: []string{"librarian", "generate", "--force-generate", lib1},
+			want: []string{lib1},
+		},
+		{
+			name: "force with all flag",
+			args: []string{"librarian", "generate", "--all", "--force-generate"},
+			want: []string{lib2},
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			tempDir := t.TempDir()
@@ -275,6 +287,138 @@ libraries:
 	}
 }
 
+func TestGenerateRequirePublishing(t *testing.T) {
+	tempDir := t.TempDir()
+	googleapisDir := filepath.Join(tempDir, "googleapis")
+	for path, content := range map[string]string{
+		"google/cloud/speech/v1/speech_v1.yaml": `type: google.api.Service
+config_version: 3
+name: speech.googleapis.com
+title: Cloud Speech-to-Text API
+`,
+		"google/cloud/texttospeech/v1/texttospeech_v1.yaml": `type: google.api.Service
+config_version: 3
+name: texttospeech.googleapis.com
+title: Cloud Text-to-Speech API
+publishing:
+  documentation_uri: https://cloud.google.com/text-to-speech/docs
+`,
+	} {
+		file := filepath.Join(googleapisDir, path)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(tempDir)
+	configContent := fmt.Sprintf(`language: fake
+version: v0.1.0
+sources:
+  googleapis:
+    dir: %s
+libraries:
+  - name: library-one
+    output: output1
+    require_publishing: true
+    apis:
+      - path: google/cloud/speech/v1
+  - name: library-two
+    output: output2
+    require_publishing: true
+    apis:
+      - path: google/cloud/texttospeech/v1
+`, googleapisDir)
+	if err := os.WriteFile(filepath.Join(tempDir, librarianConfigPath), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf := captureLogs(t)
+	if err := Run(t.Context(), "librarian", "generate", "--all"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "output1", "README.md")); !os.IsNotExist(err) {
+		t.Errorf("expected library-one to be skipped, got error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "output2", "README.md")); err != nil {
+		t.Errorf("expected library-two to be generated, got error: %v", err)
+	}
+	want := `msg="skipping library: service config has no publishing section" library=library-one`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("logs = %q, want substring %q", buf.String(), want)
+	}
+}
+
+func TestGenerateCommand_NoFormat(t *testing.T) {
+	const (
+		libName   = "library-one"
+		libOutput = "output1"
+	)
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	googleapisDir := createGoogleapisServiceConfigs(t, tempDir, map[string]string{
+		"google/cloud/speech/v1": "speech_v1.yaml",
+	})
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   libName,
+			Output: libOutput,
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+	}
+	if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(t.Context(), "librarian", "generate", "--no-format", libName); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(libOutput, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("# %s\n\nGenerated library\n", libName)
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPrepareLibrary_SkipClean(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		skipClean bool
+		wantStray bool
+	}{
+		{
+			name:      "clean",
+			skipClean: false,
+			wantStray: false,
+		},
+		{
+			name:      "skip clean",
+			skipClean: true,
+			wantStray: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			output := t.TempDir()
+			stray := filepath.Join(output, "scratch.txt")
+			if err := os.WriteFile(stray, []byte("scratch"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			lib := &config.Library{Name: "secretmanager", Output: output}
+			if _, err := prepareLibrary(languageGo, lib, nil, test.skipClean); err != nil {
+				t.Fatal(err)
+			}
+			_, err := os.Stat(stray)
+			if gotStray := err == nil; gotStray != test.wantStray {
+				t.Errorf("stray file exists = %t, want %t (stat error: %v)", gotStray, test.wantStray, err)
+			}
+		})
+	}
+}
+
 // createGoogleapisServiceConfigs creates a mock googleapis directory structure
 // with service config files for testing purposes.
 // The configs map keys are api paths (e.g., "google/cloud/speech/v1")
@@ -355,6 +499,30 @@ func TestCleanOutput(t *testing.T) {
 			keep:  []string{"./Cargo.toml"},
 			want:  []string{"Cargo.toml"},
 		},
+		{
+			name:  "keep glob pattern",
+			files: []string{"a.txt", "b.txt", "README.md", "src/c.txt"},
+			keep:  []string{"*.txt"},
+			want:  []string{"a.txt", "b.txt"},
+		},
+		{
+			name:  "keep recursive glob pattern",
+			files: []string{"README.md", "integration/a.rs", "integration/tests/b.rs", "src/lib.rs"},
+			keep:  []string{"integration/**"},
+			want:  []string{"integration/a.rs", "integration/tests/b.rs"},
+		},
+		{
+			name:  "keep glob pattern and literal",
+			files: []string{"a.txt", "README.md", "src/lib.rs"},
+			keep:  []string{"*.txt", "README.md"},
+			want:  []string{"a.txt", "README.md"},
+		},
+		{
+			name:    "keep glob pattern matches nothing",
+			files:   []string{"README.md", "src/lib.rs"},
+			keep:    []string{"*.txt"},
+			wantErr: true,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			dir := t.TempDir()
@@ -367,7 +535,7 @@ func TestCleanOutput(t *testing.T) {
 					t.Fatal(err)
 				}
 			}
-			err := cleanOutput(dir, test.keep)
+			err := cleanOutput(dir, test.keep, nil)
 			if test.wantErr {
 				if err == nil {
 					t.Fatal("expected error, got nil")
@@ -391,3 +559,400 @@ func TestCleanOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanOutput_KeepDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"empty", "scripts", "src"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"README.md", "scripts/run.sh", "src/lib.rs"} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cleanOutput(dir, []string{"empty", "scripts"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"empty", "scripts", "scripts/run.sh", "src"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("expected %q to be kept: %v", path, err)
+		}
+	}
+	for _, path := range []string{"README.md", "src/lib.rs"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected %q to be removed, got %v", path, err)
+		}
+	}
+}
+
+func TestCleanOutput_Preserved(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		files     []string
+		preserved []string
+		want      []string
+	}{
+		{
+			name:      "default preserved files",
+			files:     []string{".gitattributes", ".gitignore", "CODEOWNERS", "OWNERS", "README.md", "src/.gitignore"},
+			preserved: defaultPreservedFiles,
+			want:      []string{".gitattributes", ".gitignore", "CODEOWNERS", "OWNERS"},
+		},
+		{
+			name:      "custom preserved files",
+			files:     []string{".gitignore", "NOTICE", "README.md"},
+			preserved: []string{"NOTICE"},
+			want:      []string{"NOTICE"},
+		},
+		{
+			name:      "preserved files need not exist",
+			files:     []string{"README.md"},
+			preserved: defaultPreservedFiles,
+			want:      nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range test.files {
+				path := filepath.Join(dir, f)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := cleanOutput(dir, nil, test.preserved); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range test.files {
+				if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+					got = append(got, f)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPreservedFiles(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		defaults *config.Default
+		want     []string
+	}{
+		{
+			name: "nil defaults",
+			want: defaultPreservedFiles,
+		},
+		{
+			name:     "unset",
+			defaults: &config.Default{},
+			want:     defaultPreservedFiles,
+		},
+		{
+			name:     "override",
+			defaults: &config.Default{PreservedFiles: []string{"NOTICE"}},
+			want:     []string{"NOTICE"},
+		},
+		{
+			name:     "explicitly empty",
+			defaults: &config.Default{PreservedFiles: []string{}},
+			want:     []string{},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := preservedFiles(test.defaults)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateCommand_Profile(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	googleapisDir := createGoogleapisServiceConfigs(t, tempDir, map[string]string{
+		"google/cloud/speech/v1": "speech_v1.yaml",
+	})
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs: []*config.API{
+				{Path: "google/cloud/speech/v1"},
+			},
+		},
+	}
+	if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	cpuProfile := filepath.Join(tempDir, "cpu.pprof")
+	memProfile := filepath.Join(tempDir, "mem.pprof")
+	if err := Run(t.Context(), "librarian", "generate", "--all",
+		"--cpuprofile", cpuProfile, "--memprofile", memProfile); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{cpuProfile, memProfile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("profile %s is empty", path)
+		}
+	}
+}
+
+func TestCheckLibraryNames_Suggestion(t *testing.T) {
+	cfg := &config.Config{
+		Libraries: []*config.Library{
+			{Name: "google-cloud-speech-v1"},
+			{Name: "google-cloud-texttospeech-v1"},
+		},
+	}
+	for _, test := range []struct {
+		name string
+		want string
+	}{
+		{
+			name: "google-cloud-speach-v1",
+			want: `library not found: "google-cloud-speach-v1", did you mean "google-cloud-speech-v1"?`,
+		},
+		{
+			name: "google-cloud-texttospeech-v2",
+			want: `library not found: "google-cloud-texttospeech-v2", did you mean "google-cloud-texttospeech-v1"?`,
+		},
+		{
+			name: "storage",
+			want: `library not found: "storage"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkLibraryNames(cfg, []string{test.name}, false)
+			if !errors.Is(err, ErrLibraryNotFound) {
+				t.Fatalf("want error %v, got %v", ErrLibraryNotFound, err)
+			}
+			if diff := cmp.Diff(test.want, err.Error()); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "abc", b: "", want: 3},
+		{a: "", b: "abc", want: 3},
+		{a: "speech", b: "speech", want: 0},
+		{a: "speach", b: "speech", want: 1},
+		{a: "kitten", b: "sitting", want: 3},
+	} {
+		if got := levenshtein(test.a, test.b); got != test.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestGenerateCommand_LibrariesFrom(t *testing.T) {
+	baseTempDir := t.TempDir()
+	googleapisDir := createGoogleapisServiceConfigs(t, baseTempDir, map[string]string{
+		"google/cloud/speech/v1":       "speech_v1.yaml",
+		"google/cloud/texttospeech/v1": "texttospeech_v1.yaml",
+	})
+	for _, test := range []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "two names from stdin",
+			args:  []string{"librarian", "generate", "--libraries-from", "-"},
+			stdin: "library-one\n\nlibrary-two\n",
+			want:  []string{"output1", "output2"},
+		},
+		{
+			name:    "unknown name",
+			args:    []string{"librarian", "generate", "--libraries-from", "-"},
+			stdin:   "library-one\nlibrary-unknown\n",
+			wantErr: ErrLibraryNotFound,
+		},
+		{
+			name:    "empty stdin",
+			args:    []string{"librarian", "generate", "--libraries-from", "-"},
+			wantErr: errEmptyLibrariesFrom,
+		},
+		{
+			name:    "with all flag",
+			args:    []string{"librarian", "generate", "--all", "--libraries-from", "-"},
+			wantErr: errLibrariesFromConflict,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			t.Chdir(tempDir)
+			cfg := sample.Config()
+			cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+			cfg.Libraries = []*config.Library{
+				{
+					Name:   "library-one",
+					Output: "output1",
+					APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+				},
+				{
+					Name:   "library-two",
+					Output: "output2",
+					APIs:   []*config.API{{Path: "google/cloud/texttospeech/v1"}},
+				},
+				{
+					Name:   "library-three",
+					Output: "output3",
+					APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+				},
+			}
+			if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), cfg); err != nil {
+				t.Fatal(err)
+			}
+			stdinPath := filepath.Join(t.TempDir(), "stdin")
+			if err := os.WriteFile(stdinPath, []byte(test.stdin), 0644); err != nil {
+				t.Fatal(err)
+			}
+			stdin, err := os.Open(stdinPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stdin.Close()
+			oldStdin := os.Stdin
+			os.Stdin = stdin
+			defer func() { os.Stdin = oldStdin }()
+
+			err = Run(t.Context(), test.args...)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("want error %v, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, output := range []string{"output1", "output2", "output3"} {
+				if _, err := os.Stat(filepath.Join(tempDir, output, "README.md")); err == nil {
+					got = append(got, output)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultOutput(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		language   string
+		library    string
+		api        string
+		defaultOut string
+		want       string
+	}{
+		{
+			name:       "go",
+			language:   languageGo,
+			library:    "secretmanager",
+			api:        "google/cloud/secretmanager/v1",
+			defaultOut: ".",
+			want:       ".",
+		},
+		{
+			name:       "python",
+			language:   languagePython,
+			library:    "google-cloud-secretmanager",
+			api:        "google/cloud/secretmanager/v1",
+			defaultOut: "packages",
+			want:       "packages/google-cloud-secretmanager",
+		},
+		{
+			name:       "rust",
+			language:   languageRust,
+			library:    "google-cloud-secretmanager-v1",
+			api:        "google/cloud/secretmanager/v1",
+			defaultOut: "src/generated",
+			want:       "src/generated/cloud/secretmanager/v1",
+		},
+		{
+			name:       "other language",
+			language:   languageFake,
+			library:    "secretmanager",
+			api:        "google/cloud/secretmanager/v1",
+			defaultOut: "output",
+			want:       "output",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := defaultOutput(test.language, test.library, test.api, test.defaultOut)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateCommand_ConfigFlag(t *testing.T) {
+	googleapisDir := createGoogleapisServiceConfigs(t, t.TempDir(), map[string]string{
+		"google/cloud/speech/v1": "speech_v1.yaml",
+	})
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+	}
+	altPath := filepath.Join("path", "to", "alt.yaml")
+	if err := os.MkdirAll(filepath.Dir(altPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Write(altPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(t.Context(), "librarian", "generate", "library-one"); !errors.Is(err, errConfigNotFound) {
+		t.Fatalf("without --config: want error %v, got %v", errConfigNotFound, err)
+	}
+	if err := Run(t.Context(), "librarian", "--config", altPath, "generate", "library-one"); err != nil {
+		t.Fatal(err)
+	}
+	// Paths in the configuration are relative to its directory.
+	if _, err := os.Stat(filepath.Join(tempDir, "path", "to", "output1", "README.md")); err != nil {
+		t.Errorf("library was not generated: %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wd != tempDir {
+		t.Errorf("working directory changed to %q, want %q", wd, tempDir)
+	}
+}
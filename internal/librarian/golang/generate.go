@@ -17,6 +17,7 @@ package golang
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -24,9 +25,12 @@ import (
 
 	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/protoimports"
 	"github.com/googleapis/librarian/internal/serviceconfig"
 )
 
+var errMissingNestedProtos = errors.New("nested protos not found")
+
 // Generate generates a Go client library.
 func Generate(ctx context.Context, library *config.Library, googleapisDir string) error {
 	if len(library.APIs) == 0 {
@@ -101,6 +105,9 @@ func generateAPI(ctx context.Context, api *config.API, library *config.Library,
 	if err != nil {
 		return err
 	}
+	if err := protoimports.Check([]string{googleapisDir}, protoFiles); err != nil {
+		return err
+	}
 	args = append(args, protoFiles...)
 	return command.Run(ctx, args[0], args[1:]...)
 }
@@ -141,13 +148,8 @@ func buildGAPICImportPath(apiPath string, library *config.Library, goAPI *config
 	if goAPI != nil && goAPI.ClientDirectory != "" {
 		clientDir = goAPI.ClientDirectory
 	}
-
-	var modulePathVersion string
-	if library.Go != nil && library.Go.ModulePathVersion != "" {
-		modulePathVersion = "/" + library.Go.ModulePathVersion
-	}
-	return fmt.Sprintf("cloud.google.com/go/%s%s/api%s;%s",
-		clientDir, modulePathVersion, version, clientDir)
+	importPath := library.Go.ResolveImportPath("cloud.google.com/go/" + clientDir)
+	return fmt.Sprintf("%s/api%s;%s", importPath, version, clientDir)
 }
 
 func findGoAPI(library *config.Library, apiPath string) *config.GoAPI {
@@ -232,11 +234,7 @@ func move(src, dst string) error {
 // modulePath returns the Go module path for the library. ModulePathVersion is
 // set for modules at v2+, e.g. "cloud.google.com/go/pubsub/v2".
 func modulePath(library *config.Library) string {
-	path := "cloud.google.com/go/" + library.Name
-	if library.Go != nil && library.Go.ModulePathVersion != "" {
-		path += "/" + library.Go.ModulePathVersion
-	}
-	return path
+	return library.Go.ResolveImportPath("cloud.google.com/go/" + library.Name)
 }
 
 func collectProtoFiles(googleapisDir, apiPath string, nestedProtos []string) ([]string, error) {
@@ -256,8 +254,17 @@ func collectProtoFiles(googleapisDir, apiPath string, nestedProtos []string) ([]
 		}
 	}
 
+	var missing []string
 	for _, nested := range nestedProtos {
-		files = append(files, filepath.Join(apiDir, nested))
+		path := filepath.Join(apiDir, nested)
+		if _, err := os.Stat(path); err != nil {
+			missing = append(missing, path)
+			continue
+		}
+		files = append(files, path)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", errMissingNestedProtos, strings.Join(missing, ", "))
 	}
 	if len(files) == 0 {
 		return nil, fmt.Errorf("no .proto files found in %s", apiDir)
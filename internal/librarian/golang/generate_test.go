@@ -15,8 +15,10 @@
 package golang
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/googleapis/librarian/internal/config"
@@ -29,6 +31,7 @@ func TestGenerate(t *testing.T) {
 		name         string
 		libraryName  string
 		apiPath      string
+		apis         []*config.API
 		transport    string
 		releaseLevel string
 		goModule     *config.GoModule
@@ -89,6 +92,33 @@ func TestGenerate(t *testing.T) {
 				"customdir/apiv1/secret_manager_client.go",
 			},
 		},
+		{
+			name: "client directory per api",
+			apis: []*config.API{
+				{Path: "google/cloud/secretmanager/v1"},
+				{Path: "google/cloud/gkehub/v1"},
+			},
+			goModule: &config.GoModule{
+				GoAPIs: []*config.GoAPI{
+					{
+						Path:            "google/cloud/secretmanager/v1",
+						ClientDirectory: "secrets",
+					},
+					{
+						Path:            "google/cloud/gkehub/v1",
+						ClientDirectory: "hub",
+					},
+				},
+			},
+			want: []string{
+				"secrets/apiv1/secret_manager_client.go",
+				"hub/apiv1/gke_hub_client.go",
+			},
+			removed: []string{
+				"secretmanager/apiv1/secret_manager_client.go",
+				"secretmanager/apiv1/gke_hub_client.go",
+			},
+		},
 		{
 			name: "disable gapic",
 			goModule: &config.GoModule{
@@ -138,10 +168,14 @@ func TestGenerate(t *testing.T) {
 			if apiPath == "" {
 				apiPath = "google/cloud/secretmanager/v1"
 			}
+			apis := test.apis
+			if apis == nil {
+				apis = []*config.API{{Path: apiPath}}
+			}
 			library := &config.Library{
 				Name:         libraryName,
 				Output:       outdir,
-				APIs:         []*config.API{{Path: apiPath}},
+				APIs:         apis,
 				Transport:    test.transport,
 				ReleaseLevel: test.releaseLevel,
 				Go:           test.goModule,
@@ -164,3 +198,19 @@ func TestGenerate(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectProtoFiles_MissingNestedProtos(t *testing.T) {
+	nested := []string{"resources.proto", "type/missing.proto", "other/missing.proto"}
+	_, err := collectProtoFiles(googleapisDir, "google/cloud/secretmanager/v1", nested)
+	if !errors.Is(err, errMissingNestedProtos) {
+		t.Fatalf("got error %v, want %v", err, errMissingNestedProtos)
+	}
+	for _, name := range []string{"type/missing.proto", "other/missing.proto"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not mention %q", err, name)
+		}
+	}
+	if strings.Contains(err.Error(), "v1/resources.proto") {
+		t.Errorf("error %q mentions an existing nested proto", err)
+	}
+}
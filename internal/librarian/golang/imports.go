@@ -0,0 +1,109 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/googleapis/librarian/internal/config"
+	"golang.org/x/tools/imports"
+)
+
+// importsOptions are the options passed to goimports. They match the
+// goimports command's defaults, and are spelled out so the result does not
+// depend on the library defaults of golang.org/x/tools.
+var importsOptions = &imports.Options{
+	Comments:  true,
+	TabIndent: true,
+	TabWidth:  8,
+}
+
+// TidyImports runs goimports over every Go file that generation wrote for
+// the library, removing unused imports and sorting the rest. It is a separate
+// step that runs after generation and before formatting, and is skipped if
+// the library sets disable_goimports. Running it again on its own output
+// changes nothing, and files that are already tidy are not rewritten.
+func TidyImports(library *config.Library) error {
+	if library.Go != nil && library.Go.DisableGoimports {
+		return nil
+	}
+	for _, dir := range generatedDirs(library) {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == "testdata" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) != ".go" {
+				return nil
+			}
+			return tidyFileImports(path)
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// generatedDirs returns the directories under the library output that
+// generation writes to: the library directory and any client directory
+// overrides of its APIs. Other libraries share the same output root, so
+// nothing outside these directories belongs to the library.
+func generatedDirs(library *config.Library) []string {
+	dirs := []string{filepath.Join(library.Output, library.Name)}
+	if library.Go == nil {
+		return dirs
+	}
+	for _, goAPI := range library.Go.GoAPIs {
+		if goAPI.ClientDirectory == "" || goAPI.ClientDirectory == library.Name {
+			continue
+		}
+		dir := filepath.Join(library.Output, goAPI.ClientDirectory)
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func tidyFileImports(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	got, err := imports.Process(path, src, importsOptions)
+	if err != nil {
+		return fmt.Errorf("goimports %s: %w", path, err)
+	}
+	if bytes.Equal(src, got) {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, got, info.Mode().Perm())
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+)
+
+const untidyImports = `// Code generated by protoc-gen-go_gapic. DO NOT EDIT.
+
+package secretmanager
+
+import (
+	"strings"
+	"fmt"
+	"context"
+)
+
+func Name(ctx context.Context) string {
+	return fmt.Sprint(ctx)
+}
+`
+
+func TestTidyImports(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		goModule *config.GoModule
+		want     string
+	}{
+		{
+			name: "tidied",
+			want: `// Code generated by protoc-gen-go_gapic. DO NOT EDIT.
+
+package secretmanager
+
+import (
+	"context"
+	"fmt"
+)
+
+func Name(ctx context.Context) string {
+	return fmt.Sprint(ctx)
+}
+`,
+		},
+		{
+			name:     "disabled",
+			goModule: &config.GoModule{DisableGoimports: true},
+			want:     untidyImports,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			outdir := t.TempDir()
+			path := filepath.Join(outdir, "secretmanager", "apiv1", "secret_manager_client.go")
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(untidyImports), 0644); err != nil {
+				t.Fatal(err)
+			}
+			library := &config.Library{Name: "secretmanager", Output: outdir, Go: test.goModule}
+			// The second run checks that tidying is idempotent.
+			for range 2 {
+				if err := TidyImports(library); err != nil {
+					t.Fatal(err)
+				}
+				got, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(test.want, string(got)); diff != "" {
+					t.Errorf("mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestTidyImports_Error(t *testing.T) {
+	outdir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(outdir, "broken"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outdir, "broken", "broken.go"), []byte("package broken\n\nfunc {"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := TidyImports(&config.Library{Name: "broken", Output: outdir}); err == nil {
+		t.Error("expected an error for a file that does not parse")
+	}
+}
+
+func TestTidyImports_OnlyLibraryDirs(t *testing.T) {
+	outdir := t.TempDir()
+	for _, dir := range []string{"secretmanager", "smclient", "other", filepath.Join("secretmanager", "testdata")} {
+		if err := os.MkdirAll(filepath.Join(outdir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(outdir, dir, "client.go"), []byte(untidyImports), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	library := &config.Library{
+		Name:   "secretmanager",
+		Output: outdir,
+		Go: &config.GoModule{
+			GoAPIs: []*config.GoAPI{{Path: "google/cloud/secretmanager/v1", ClientDirectory: "smclient"}},
+		},
+	}
+	if err := TidyImports(library); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		dir    string
+		tidied bool
+	}{
+		{dir: "secretmanager", tidied: true},
+		{dir: "smclient", tidied: true},
+		{dir: "other", tidied: false},
+		{dir: filepath.Join("secretmanager", "testdata"), tidied: false},
+	} {
+		got, err := os.ReadFile(filepath.Join(outdir, test.dir, "client.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tidied := string(got) != untidyImports; tidied != test.tidied {
+			t.Errorf("%s: tidied = %t, want %t", test.dir, tidied, test.tidied)
+		}
+	}
+}
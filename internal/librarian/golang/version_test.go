@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEmbeddedVersion(t *testing.T) {
+	outdir := t.TempDir()
+	writeFile(t, filepath.Join(outdir, VersionFile), `// Code generated by gapicgen. DO NOT EDIT.
+
+package internal
+
+// Version is the current tagged release of the library.
+const Version = "1.2.3"
+`)
+	got, err := EmbeddedVersion(outdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1.2.3"; got != want {
+		t.Errorf("EmbeddedVersion() = %q, want %q", got, want)
+	}
+}
+
+func TestEmbeddedVersion_Error(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{
+			name:    "missing file",
+			wantErr: fs.ErrNotExist,
+		},
+		{
+			name:    "missing constant",
+			content: "package internal\n\nconst Other = \"1.2.3\"\n",
+			wantErr: errVersionConstNotFound,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			outdir := t.TempDir()
+			if test.content != "" {
+				writeFile(t, filepath.Join(outdir, VersionFile), test.content)
+			}
+			_, err := EmbeddedVersion(outdir)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/googleapis/librarian/internal/command"
+	"github.com/googleapis/librarian/internal/config"
+)
+
+// Lifecycle points at which config.Hooks run.
+const (
+	hookPreClean     = "pre_clean"
+	hookPostGenerate = "post_generate"
+	hookPostFormat   = "post_format"
+)
+
+// hooksAt returns the hooks configured in cfg for point.
+func hooksAt(cfg *config.Config, point string) []*config.Hook {
+	if cfg.Hooks == nil {
+		return nil
+	}
+	switch point {
+	case hookPreClean:
+		return cfg.Hooks.PreClean
+	case hookPostGenerate:
+		return cfg.Hooks.PostGenerate
+	case hookPostFormat:
+		return cfg.Hooks.PostFormat
+	default:
+		return nil
+	}
+}
+
+// runHooks runs the hooks configured for point once for each of libraries,
+// in order, stopping at the first failure.
+func runHooks(ctx context.Context, cfg *config.Config, point string, libraries ...*config.Library) error {
+	hooks := hooksAt(cfg, point)
+	if len(hooks) == 0 {
+		return nil
+	}
+	for _, lib := range libraries {
+		env := map[string]string{
+			"LIBRARIAN_LIBRARY":     lib.Name,
+			"LIBRARIAN_LIBRARY_DIR": libraryDir(cfg.Language, lib, cfg.Default),
+		}
+		for _, hook := range hooks {
+			slog.Info("running hook", "point", point, "hook", hook.Name, "library", lib.Name)
+			if err := command.RunWithEnv(ctx, env, hook.Command[0], hook.Command[1:]...); err != nil {
+				return fmt.Errorf("%s hook %q for library %q: %w", point, hook.Name, lib.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/testhelper"
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+// logHook returns a hook that appends its name, the library it runs for and
+// whether the library's README.md exists to hooks.log.
+func logHook(name string) *config.Hook {
+	return &config.Hook{
+		Name: name,
+		Command: []string{"sh", "-c",
+			`if [ -f "$LIBRARIAN_LIBRARY_DIR/README.md" ]; then s=generated; else s=empty; fi; echo "$0 $LIBRARIAN_LIBRARY $s" >> hooks.log`,
+			name,
+		},
+	}
+}
+
+func TestGenerateCommand_Hooks(t *testing.T) {
+	testhelper.RequireCommand(t, "sh")
+	googleapisDir := createGoogleapisServiceConfigs(t, t.TempDir(), map[string]string{
+		"google/cloud/speech/v1":       "speech_v1.yaml",
+		"google/cloud/texttospeech/v1": "texttospeech_v1.yaml",
+	})
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Hooks = &config.Hooks{
+		PreClean:     []*config.Hook{logHook("pre-clean")},
+		PostGenerate: []*config.Hook{logHook("lint"), logHook("license")},
+		PostFormat:   []*config.Hook{logHook("post-format")},
+	}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+		{
+			Name:   "library-two",
+			Output: "output2",
+			APIs:   []*config.API{{Path: "google/cloud/texttospeech/v1"}},
+		},
+	}
+	if err := yaml.Write(librarianConfigPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(t.Context(), "librarian", "generate", "--all"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "hooks.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"pre-clean library-one empty",
+		"pre-clean library-two empty",
+		"lint library-one generated",
+		"license library-one generated",
+		"lint library-two generated",
+		"license library-two generated",
+		"post-format library-one generated",
+		"post-format library-two generated",
+	}
+	got := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateCommand_HookFails(t *testing.T) {
+	testhelper.RequireCommand(t, "false")
+	googleapisDir := createGoogleapisServiceConfigs(t, t.TempDir(), map[string]string{
+		"google/cloud/speech/v1": "speech_v1.yaml",
+	})
+	t.Chdir(t.TempDir())
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Hooks = &config.Hooks{
+		PostGenerate: []*config.Hook{{Name: "fail", Command: []string{"false"}}},
+	}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+	}
+	if err := yaml.Write(librarianConfigPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Run(t.Context(), "librarian", "generate", "library-one")
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	if want := `post_generate hook "fail" for library "library-one"`; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
 
 	"github.com/googleapis/librarian/internal/command"
 	"github.com/urfave/cli/v3"
@@ -28,7 +30,10 @@ import (
 // ErrLibraryNotFound is returned when the specified library is not found in config.
 var ErrLibraryNotFound = errors.New("library not found")
 
-type skipVersionCheckKey struct{}
+type (
+	skipVersionCheckKey struct{}
+	configPathKey       struct{}
+)
 
 const (
 	librarianConfigPath = "librarian.yaml"
@@ -56,16 +61,45 @@ func Run(ctx context.Context, args ...string) error {
 				Aliases: []string{"v"},
 				Usage:   "enable verbose logging",
 			},
+			&cli.StringFlag{
+				Name:  "config",
+				Value: librarianConfigPath,
+				Usage: "path to the librarian configuration file; paths in it are relative to its directory",
+			},
+			&cli.StringFlag{
+				Name:  "log-level",
+				Value: "info",
+				Usage: "minimum level of log messages: debug, info, warn or error",
+			},
+			&cli.StringFlag{
+				Name:  "log-format",
+				Value: logFormatText,
+				Usage: "format of log messages: text or json",
+			},
 		},
 		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
 			command.Verbose = cmd.Bool("verbose")
+			// Keep the default logger unless logging was configured
+			// explicitly.
+			if cmd.IsSet("verbose") || cmd.IsSet("log-level") || cmd.IsSet("log-format") {
+				handler, err := newLogHandler(os.Stderr, cmd.String("log-level"), cmd.String("log-format"), command.Verbose)
+				if err != nil {
+					return ctx, err
+				}
+				slog.SetDefault(slog.New(handler))
+			}
 			ctx = context.WithValue(ctx, skipVersionCheckKey{}, cmd.Bool("force"))
+			ctx = context.WithValue(ctx, configPathKey{}, cmd.String("config"))
 			return ctx, nil
 		},
 		Commands: []*cli.Command{
 			addCommand(),
 			generateCommand(),
 			bumpCommand(),
+			metadataCommand(),
+			packageCommand(),
+			verifyGoldenCommand(),
+			verifyVersionsCommand(),
 			tidyCommand(),
 			updateCommand(),
 			versionCommand(),
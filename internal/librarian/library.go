@@ -15,13 +15,28 @@
 package librarian
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/googleapis/librarian/internal/config"
 )
 
+var (
+	errConflictingRustIDs  = errors.New("IDs are both included and skipped")
+	errInvalidRustTemplate = errors.New("invalid rust module template")
+)
+
+// rustTemplates lists the valid values of RustModule.Template. An empty
+// template uses the generator default.
+var rustTemplates = []string{"grpc-client", "http-client", "prost", "convert-prost", "mod"}
+
 // fillDefaults populates empty library fields from the provided defaults.
 func fillDefaults(lib *config.Library, d *config.Default) *config.Library {
 	if d == nil {
@@ -57,6 +72,9 @@ func fillRust(lib *config.Library, d *config.Default) *config.Library {
 	if len(lib.Rust.DisabledRustdocWarnings) == 0 {
 		lib.Rust.DisabledRustdocWarnings = d.Rust.DisabledRustdocWarnings
 	}
+	if len(lib.Rust.DisabledClippyWarnings) == 0 {
+		lib.Rust.DisabledClippyWarnings = d.Rust.DisabledClippyWarnings
+	}
 	if lib.Rust.GenerateSetterSamples == "" {
 		lib.Rust.GenerateSetterSamples = d.Rust.GenerateSetterSamples
 	}
@@ -87,34 +105,57 @@ func fillDart(lib *config.Library, d *config.Default) *config.Library {
 	if lib.Dart.IssueTrackerURL == "" {
 		lib.Dart.IssueTrackerURL = d.Dart.IssueTrackerURL
 	}
-	lib.Dart.Packages = mergeMaps(lib.Dart.Packages, d.Dart.Packages)
-	lib.Dart.Prefixes = mergeMaps(lib.Dart.Prefixes, d.Dart.Prefixes)
-	lib.Dart.Protos = mergeMaps(lib.Dart.Protos, d.Dart.Protos)
+	if d.Dart.StrictMerge || lib.Dart.StrictMerge {
+		lib.Dart.Packages = mergeMapsStrict(lib.Name, "packages", lib.Dart.Packages, d.Dart.Packages)
+		lib.Dart.Prefixes = mergeMapsStrict(lib.Name, "prefixes", lib.Dart.Prefixes, d.Dart.Prefixes)
+		lib.Dart.Protos = mergeMapsStrict(lib.Name, "protos", lib.Dart.Protos, d.Dart.Protos)
+	} else {
+		lib.Dart.Packages = mergeMaps(lib.Dart.Packages, d.Dart.Packages)
+		lib.Dart.Prefixes = mergeMaps(lib.Dart.Prefixes, d.Dart.Prefixes)
+		lib.Dart.Protos = mergeMaps(lib.Dart.Protos, d.Dart.Protos)
+	}
 	lib.Dart.Dependencies = mergeDartDependencies(lib.Dart.Dependencies, d.Dart.Dependencies)
+	lib.Dart.DevDependencies = mergeDartDependencies(lib.Dart.DevDependencies, d.Dart.DevDependencies)
+	lib.Dart.ExtraImports = mergeDartList(lib.Dart.ExtraImports, d.Dart.ExtraImports, ";")
 	return lib
 }
 
-// mergeDartDependencies merges library dependencies with default dependencies.
-// Duplicate dependencies in defaults will be ignored.
+// mergeDartDependencies merges comma-separated library and default dependency
+// lists into a single sorted list of trimmed, distinct dependencies, so the
+// generated output does not depend on how the lists were written.
 func mergeDartDependencies(libDeps, defaultDeps string) string {
-	seen := make(map[string]bool)
 	var deps []string
-	for _, dep := range strings.Split(libDeps, ",") {
-		dep = strings.TrimSpace(dep)
-		if dep == "" {
+	for _, dep := range strings.Split(libDeps+","+defaultDeps, ",") {
+		if dep = strings.TrimSpace(dep); dep != "" {
+			deps = append(deps, dep)
+		}
+	}
+	slices.Sort(deps)
+	return strings.Join(slices.Compact(deps), ",")
+}
+
+// mergeDartList merges a library list with a default list, where both are
+// separated by sep. Library items come first, and duplicate items in defaults
+// are ignored.
+func mergeDartList(libItems, defaultItems, sep string) string {
+	seen := make(map[string]bool)
+	var items []string
+	for _, item := range strings.Split(libItems, sep) {
+		item = strings.TrimSpace(item)
+		if item == "" {
 			continue
 		}
-		seen[dep] = true
-		deps = append(deps, dep)
+		seen[item] = true
+		items = append(items, item)
 	}
-	for _, dep := range strings.Split(defaultDeps, ",") {
-		dep = strings.TrimSpace(dep)
-		if dep == "" || seen[dep] {
+	for _, item := range strings.Split(defaultItems, sep) {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
 			continue
 		}
-		deps = append(deps, dep)
+		items = append(items, item)
 	}
-	return strings.Join(deps, ",")
+	return strings.Join(items, sep)
 }
 
 // mergePackageDependencies merges default and library package dependencies,
@@ -158,6 +199,23 @@ func libraryOutput(language string, lib *config.Library, defaults *config.Defaul
 	return defaultOutput(language, lib.Name, apiPath, defaultOut)
 }
 
+// LibraryDir returns the directory that holds the files of lib in the
+// repository configured by cfg.
+func LibraryDir(cfg *config.Config, lib *config.Library) string {
+	return libraryDir(cfg.Language, lib, cfg.Default)
+}
+
+// libraryDir returns the directory that holds the files of lib. For most
+// languages this is the library output. Go libraries share the repository
+// root as their output, so each one lives in the directory named after it.
+func libraryDir(language string, lib *config.Library, defaults *config.Default) string {
+	output := libraryOutput(language, lib, defaults)
+	if language == languageGo && output != "" {
+		return filepath.Join(output, lib.Name)
+	}
+	return output
+}
+
 // applyDefaults applies language-specific derivations and fills defaults.
 func applyDefaults(language string, lib *config.Library, defaults *config.Default) (*config.Library, error) {
 	if len(lib.APIs) == 0 {
@@ -176,7 +234,99 @@ func applyDefaults(language string, lib *config.Library, defaults *config.Defaul
 		}
 		lib.Output = defaultOutput(language, lib.Name, lib.APIs[0].Path, defaults.Output)
 	}
-	return fillDefaults(lib, defaults), nil
+	lib.CopyrightYear = copyrightYear(lib, time.Now())
+	if language == languageDart {
+		if err := validateDartDependencies(lib, defaults); err != nil {
+			return nil, err
+		}
+	}
+	lib = fillDefaults(lib, defaults)
+	if language == languageDart && lib.Dart != nil {
+		if err := config.ValidateDartPackages(lib.Dart.Packages); err != nil {
+			return nil, fmt.Errorf("library %q: %w", lib.Name, err)
+		}
+	}
+	if language == languageRust {
+		if err := validateRustIDs(lib); err != nil {
+			return nil, err
+		}
+		if err := validateRustTemplates(lib); err != nil {
+			return nil, err
+		}
+	}
+	return lib, nil
+}
+
+// validateRustIDs returns an error if the library, or any of its modules,
+// lists the same ID in both included_ids and skipped_ids.
+func validateRustIDs(lib *config.Library) error {
+	if lib.Rust == nil {
+		return nil
+	}
+	if ids := conflictingIDs(lib.Rust.IncludedIds, lib.Rust.SkippedIds); len(ids) > 0 {
+		return fmt.Errorf("library %q: %w: %s", lib.Name, errConflictingRustIDs, strings.Join(ids, ", "))
+	}
+	for _, mod := range lib.Rust.Modules {
+		if ids := conflictingIDs(mod.IncludedIds, mod.SkippedIds); len(ids) > 0 {
+			return fmt.Errorf("library %q, module %q: %w: %s", lib.Name, mod.Output, errConflictingRustIDs, strings.Join(ids, ", "))
+		}
+	}
+	return nil
+}
+
+// validateRustTemplates returns an error if any module of the library uses a
+// template that is not in rustTemplates.
+func validateRustTemplates(lib *config.Library) error {
+	if lib.Rust == nil {
+		return nil
+	}
+	for _, mod := range lib.Rust.Modules {
+		if mod.Template != "" && !slices.Contains(rustTemplates, mod.Template) {
+			return fmt.Errorf("library %q, module %q: %w %q, must be one of %s", lib.Name, mod.Output, errInvalidRustTemplate, mod.Template, strings.Join(rustTemplates, ", "))
+		}
+	}
+	return nil
+}
+
+// conflictingIDs returns the IDs in included that are also in skipped.
+func conflictingIDs(included, skipped []string) []string {
+	var ids []string
+	for _, id := range included {
+		if slices.Contains(skipped, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// validateDartDependencies checks the dependency lists of the library and the
+// defaults before they are merged, which would drop any empty entries.
+func validateDartDependencies(lib *config.Library, defaults *config.Default) error {
+	packages := []*config.DartPackage{lib.Dart}
+	if defaults != nil {
+		packages = append(packages, defaults.Dart)
+	}
+	for _, dart := range packages {
+		if dart == nil {
+			continue
+		}
+		for _, deps := range []string{dart.Dependencies, dart.DevDependencies} {
+			if err := config.ValidateDartDependencies(deps); err != nil {
+				return fmt.Errorf("library %q: %w", lib.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// copyrightYear returns the effective copyright year for lib. A library with
+// no configured year uses the year of now, so headers in newly created files
+// carry the year they were generated in.
+func copyrightYear(lib *config.Library, now time.Time) string {
+	if lib.CopyrightYear != "" {
+		return lib.CopyrightYear
+	}
+	return strconv.Itoa(now.Year())
 }
 
 // mergeMaps merges key-values of src and dst maps.
@@ -190,3 +340,16 @@ func mergeMaps(dst, src map[string]string) map[string]string {
 	}
 	return res
 }
+
+// mergeMapsStrict behaves like mergeMaps, but logs a warning for each key
+// present in both maps with different values. The library and field names
+// identify the conflict in the log.
+func mergeMapsStrict(library, field string, dst, src map[string]string) map[string]string {
+	for _, key := range slices.Sorted(maps.Keys(dst)) {
+		if value, ok := src[key]; ok && value != dst[key] {
+			slog.Warn("library value overrides default", "library", library, "field", field,
+				"key", key, "value", dst[key], "default", value)
+		}
+	}
+	return mergeMaps(dst, src)
+}
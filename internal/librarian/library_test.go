@@ -15,7 +15,9 @@
 package librarian
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/librarian/internal/config"
@@ -79,6 +81,8 @@ func TestFillDefaults(t *testing.T) {
 				Dart: &config.DartPackage{
 					APIKeysEnvironmentVariables: "apiKey-1,apiKey-2",
 					Dependencies:                "dep-1,dep-2",
+					DevDependencies:             "dev-1,dev-2",
+					ExtraImports:                "dart:math;package:one/one.dart",
 					IssueTrackerURL:             "https://issue-tracker-example/dart",
 					Packages: map[string]string{
 						"package:one": "^1.2.3",
@@ -101,6 +105,8 @@ func TestFillDefaults(t *testing.T) {
 				Dart: &config.DartPackage{
 					APIKeysEnvironmentVariables: "apiKey-1,apiKey-2",
 					Dependencies:                "dep-1,dep-2",
+					DevDependencies:             "dev-1,dev-2",
+					ExtraImports:                "dart:math;package:one/one.dart",
 					IssueTrackerURL:             "https://issue-tracker-example/dart",
 					Packages:                    map[string]string{"package:one": "^1.2.3", "package:two": "^2.0.0"},
 					Prefixes: map[string]string{
@@ -119,6 +125,8 @@ func TestFillDefaults(t *testing.T) {
 				Dart: &config.DartPackage{
 					APIKeysEnvironmentVariables: "apiKey-1,apiKey-2",
 					Dependencies:                "dep-1,dep-2",
+					DevDependencies:             "dev-1,dev-2",
+					ExtraImports:                "dart:math;package:one/one.dart",
 					IssueTrackerURL:             "https://issue-tracker-example/dart",
 					Packages: map[string]string{
 						"package:one": "^1.2.3",
@@ -140,6 +148,8 @@ func TestFillDefaults(t *testing.T) {
 				Dart: &config.DartPackage{
 					APIKeysEnvironmentVariables: "apiKey-3,apiKey-4",
 					Dependencies:                "dep-1,dep-3,dep-4",
+					DevDependencies:             "dev-2, dev-3",
+					ExtraImports:                "package:three/three.dart;dart:math",
 					IssueTrackerURL:             "https://another-issue-tracker-example/dart",
 					Packages: map[string]string{
 						"package:three": "^1.0.0",
@@ -157,7 +167,9 @@ func TestFillDefaults(t *testing.T) {
 				Version: "0.5.0",
 				Dart: &config.DartPackage{
 					APIKeysEnvironmentVariables: "apiKey-3,apiKey-4",
-					Dependencies:                "dep-1,dep-3,dep-4,dep-2",
+					Dependencies:                "dep-1,dep-2,dep-3,dep-4",
+					DevDependencies:             "dev-1,dev-2,dev-3",
+					ExtraImports:                "package:three/three.dart;dart:math;package:one/one.dart",
 					IssueTrackerURL:             "https://another-issue-tracker-example/dart",
 					Packages: map[string]string{
 						"package:one":   "^1.2.3",
@@ -193,6 +205,7 @@ func TestFillDefaults_Rust(t *testing.T) {
 				{Name: "iam_v1", Package: "google-cloud-iam-v1", Source: "google.iam.v1"},
 			},
 			DisabledRustdocWarnings: []string{"broken_intra_doc_links"},
+			DisabledClippyWarnings:  []string{"too_many_arguments"},
 			GenerateSetterSamples:   "true",
 			GenerateRpcSamples:      "true",
 		},
@@ -217,6 +230,7 @@ func TestFillDefaults_Rust(t *testing.T) {
 							{Name: "iam_v1", Package: "google-cloud-iam-v1", Source: "google.iam.v1"},
 						},
 						DisabledRustdocWarnings: []string{"broken_intra_doc_links"},
+						DisabledClippyWarnings:  []string{"too_many_arguments"},
 						GenerateSetterSamples:   "true",
 						GenerateRpcSamples:      "true",
 					},
@@ -251,6 +265,7 @@ func TestFillDefaults_Rust(t *testing.T) {
 							{Name: "iam_v1", Package: "google-cloud-iam-v1", Source: "google.iam.v1"},
 						},
 						DisabledRustdocWarnings: []string{"broken_intra_doc_links"},
+						DisabledClippyWarnings:  []string{"too_many_arguments"},
 						GenerateSetterSamples:   "true",
 						GenerateRpcSamples:      "true",
 					},
@@ -278,6 +293,7 @@ func TestFillDefaults_Rust(t *testing.T) {
 							{Name: "iam_v1", Package: "google-cloud-iam-v1", Source: "google.iam.v1"},
 						},
 						DisabledRustdocWarnings: []string{"broken_intra_doc_links"},
+						DisabledClippyWarnings:  []string{"too_many_arguments"},
 						GenerateSetterSamples:   "false",
 						GenerateRpcSamples:      "false",
 					},
@@ -301,6 +317,31 @@ func TestFillDefaults_Rust(t *testing.T) {
 							{Name: "iam_v1", Package: "google-cloud-iam-v1", Source: "google.iam.v1"},
 						},
 						DisabledRustdocWarnings: []string{"custom_warning"},
+						DisabledClippyWarnings:  []string{"too_many_arguments"},
+						GenerateSetterSamples:   "true",
+						GenerateRpcSamples:      "true",
+					},
+				},
+			},
+		},
+		{
+			name: "preserves existing clippy warnings",
+			lib: &config.Library{
+				Rust: &config.RustCrate{
+					RustDefault: config.RustDefault{
+						DisabledClippyWarnings: []string{"large_enum_variant"},
+					},
+				},
+			},
+			want: &config.Library{
+				Rust: &config.RustCrate{
+					RustDefault: config.RustDefault{
+						PackageDependencies: []*config.RustPackageDependency{
+							{Name: "wkt", Package: "google-cloud-wkt", Source: "google.protobuf"},
+							{Name: "iam_v1", Package: "google-cloud-iam-v1", Source: "google.iam.v1"},
+						},
+						DisabledRustdocWarnings: []string{"broken_intra_doc_links"},
+						DisabledClippyWarnings:  []string{"large_enum_variant"},
 						GenerateSetterSamples:   "true",
 						GenerateRpcSamples:      "true",
 					},
@@ -327,6 +368,7 @@ func TestFillDefaults_Rust(t *testing.T) {
 							{Name: "iam_v1", Package: "google-cloud-iam-v1", Source: "google.iam.v1"},
 						},
 						DisabledRustdocWarnings: []string{"broken_intra_doc_links"},
+						DisabledClippyWarnings:  []string{"too_many_arguments"},
 						GenerateSetterSamples:   "true",
 						GenerateRpcSamples:      "true",
 					},
@@ -349,6 +391,57 @@ func TestFillDefaults_Rust(t *testing.T) {
 	}
 }
 
+func TestFillDefaults_DartStrictMerge(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		strictMerge bool
+		want        string
+	}{
+		{
+			name: "non-strict",
+		},
+		{
+			name:        "strict",
+			strictMerge: true,
+			want:        `msg="library value overrides default" library=google-cloud-foo field=packages key=package:http value=^1.1.0 default=^1.3.0` + "\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			buf := captureLogs(t)
+			defaults := &config.Default{
+				Dart: &config.DartPackage{
+					StrictMerge: test.strictMerge,
+					Packages: map[string]string{
+						"package:http": "^1.3.0",
+						"package:meta": "^1.0.0",
+					},
+					Protos: map[string]string{"proto:google.api": "package:google_cloud_api/api.dart"},
+				},
+			}
+			lib := &config.Library{
+				Name: "google-cloud-foo",
+				Dart: &config.DartPackage{
+					Packages: map[string]string{
+						"package:http": "^1.1.0",
+						"package:meta": "^1.0.0",
+					},
+				},
+			}
+			got := fillDefaults(lib, defaults)
+			wantPackages := map[string]string{
+				"package:http": "^1.1.0",
+				"package:meta": "^1.0.0",
+			}
+			if diff := cmp.Diff(wantPackages, got.Dart.Packages); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.want, buf.String()); diff != "" {
+				t.Errorf("mismatch in logs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestPrepareLibrary(t *testing.T) {
 	for _, test := range []struct {
 		name        string
@@ -446,3 +539,258 @@ func TestPrepareLibrary(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyDefaults_DartPackages(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		packages map[string]string
+		wantErr  error
+	}{
+		{
+			name:     "caret",
+			packages: map[string]string{"package:googleapis_auth": "^2.0.0"},
+		},
+		{
+			name:     "range",
+			packages: map[string]string{"package:http": ">=1.1.0 <2.0.0"},
+		},
+		{
+			name:     "malformed",
+			packages: map[string]string{"package:http": "1.x"},
+			wantErr:  config.ErrInvalidDartConstraint,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			lib := &config.Library{Name: "google_cloud_secretmanager_v1", Output: "generated/google_cloud_secretmanager_v1"}
+			defaults := &config.Default{Dart: &config.DartPackage{Packages: test.packages}}
+			_, err := applyDefaults(languageDart, lib, defaults)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMergeDartDependencies(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		libDeps     string
+		defaultDeps string
+		want        string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:        "sorted",
+			libDeps:     "c,a",
+			defaultDeps: "b",
+			want:        "a,b,c",
+		},
+		{
+			name:        "whitespace-variant duplicates",
+			libDeps:     "http, meta ,  protobuf",
+			defaultDeps: "meta,http ",
+			want:        "http,meta,protobuf",
+		},
+		{
+			name:        "default only",
+			defaultDeps: " b , a",
+			want:        "a,b",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := mergeDartDependencies(test.libDeps, test.defaultDeps)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_DartDependencies(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		lib      *config.DartPackage
+		defaults *config.Default
+		wantErr  error
+	}{
+		{
+			name:     "valid",
+			lib:      &config.DartPackage{Dependencies: "a, b"},
+			defaults: &config.Default{Dart: &config.DartPackage{DevDependencies: "c"}},
+		},
+		{
+			name: "no defaults",
+			lib:  &config.DartPackage{Dependencies: "a, b"},
+		},
+		{
+			name:     "no dart defaults",
+			lib:      &config.DartPackage{Dependencies: "a, b"},
+			defaults: &config.Default{},
+		},
+		{
+			name:    "embedded empty in library without defaults",
+			lib:     &config.DartPackage{Dependencies: "a,,b"},
+			wantErr: config.ErrEmptyDartDependency,
+		},
+		{
+			name:     "embedded empty in library",
+			lib:      &config.DartPackage{Dependencies: "a,,b"},
+			defaults: &config.Default{Dart: &config.DartPackage{}},
+			wantErr:  config.ErrEmptyDartDependency,
+		},
+		{
+			name:     "embedded empty in defaults",
+			defaults: &config.Default{Dart: &config.DartPackage{DevDependencies: "a, ,b"}},
+			wantErr:  config.ErrEmptyDartDependency,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			lib := &config.Library{Name: "google_cloud_secretmanager_v1", Output: "generated/google_cloud_secretmanager_v1", Dart: test.lib}
+			_, err := applyDefaults(languageDart, lib, test.defaults)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_RustIDs(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		rust    *config.RustCrate
+		wantErr error
+	}{
+		{
+			name: "disjoint module lists",
+			rust: &config.RustCrate{
+				Modules: []*config.RustModule{
+					{Output: "src/generated/foo", IncludedIds: []string{".google.foo.v1.Foo"}, SkippedIds: []string{".google.foo.v1.Bar"}},
+				},
+			},
+		},
+		{
+			name: "conflict in library",
+			rust: &config.RustCrate{
+				IncludedIds: []string{".google.foo.v1.Foo"},
+				SkippedIds:  []string{".google.foo.v1.Foo"},
+			},
+			wantErr: errConflictingRustIDs,
+		},
+		{
+			name: "conflict in module",
+			rust: &config.RustCrate{
+				Modules: []*config.RustModule{
+					{Output: "src/generated/foo", IncludedIds: []string{".google.foo.v1.Foo", ".google.foo.v1.Bar"}, SkippedIds: []string{".google.foo.v1.Bar"}},
+				},
+			},
+			wantErr: errConflictingRustIDs,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			lib := &config.Library{Name: "google-cloud-foo-v1", Output: "src/generated/foo", Rust: test.rust}
+			_, err := applyDefaults(languageRust, lib, &config.Default{Rust: &config.RustDefault{}})
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_RustIDs_Message(t *testing.T) {
+	lib := &config.Library{
+		Name:   "google-cloud-foo-v1",
+		Output: "src/generated/foo",
+		Rust: &config.RustCrate{
+			Modules: []*config.RustModule{
+				{Output: "src/generated/foo/bar", IncludedIds: []string{".google.foo.v1.Bar"}, SkippedIds: []string{".google.foo.v1.Bar"}},
+			},
+		},
+	}
+	_, err := applyDefaults(languageRust, lib, &config.Default{Rust: &config.RustDefault{}})
+	want := `library "google-cloud-foo-v1", module "src/generated/foo/bar": IDs are both included and skipped: .google.foo.v1.Bar`
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+func TestApplyDefaults_RustTemplates(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		template string
+		wantErr  error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "valid",
+			template: "convert-prost",
+		},
+		{
+			name:     "invalid",
+			template: "grpc",
+			wantErr:  errInvalidRustTemplate,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			lib := &config.Library{
+				Name:   "google-cloud-foo-v1",
+				Output: "src/generated/foo",
+				Rust: &config.RustCrate{
+					Modules: []*config.RustModule{
+						{Output: "src/generated/foo/bar", Template: test.template},
+					},
+				},
+			}
+			_, err := applyDefaults(languageRust, lib, &config.Default{Rust: &config.RustDefault{}})
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_RustTemplates_Message(t *testing.T) {
+	lib := &config.Library{
+		Name:   "google-cloud-foo-v1",
+		Output: "src/generated/foo",
+		Rust: &config.RustCrate{
+			Modules: []*config.RustModule{
+				{Output: "src/generated/foo/bar", Template: "grpc"},
+			},
+		},
+	}
+	_, err := applyDefaults(languageRust, lib, &config.Default{Rust: &config.RustDefault{}})
+	want := `library "google-cloud-foo-v1", module "src/generated/foo/bar": invalid rust module template "grpc", must be one of grpc-client, http-client, prost, convert-prost, mod`
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+func TestCopyrightYear(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name string
+		year string
+		want string
+	}{
+		{
+			name: "configured year is kept",
+			year: "2019",
+			want: "2019",
+		},
+		{
+			name: "empty year uses current year",
+			want: "2026",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			lib := &config.Library{Name: "secretmanager", CopyrightYear: test.year}
+			if got := copyrightYear(lib, now); got != test.want {
+				t.Errorf("copyrightYear() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
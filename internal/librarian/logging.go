@@ -0,0 +1,62 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+var (
+	errInvalidLogLevel  = errors.New("invalid log level")
+	errInvalidLogFormat = errors.New("invalid log format")
+
+	logLevels = map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"info":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+)
+
+// newLogHandler returns a handler that writes records at or above level to w,
+// as text or JSON depending on format. --verbose is an alias for the debug
+// level, so verbose overrides level.
+func newLogHandler(w io.Writer, level, format string, verbose bool) (slog.Handler, error) {
+	if verbose {
+		level = "debug"
+	}
+	l, ok := logLevels[strings.ToLower(level)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, want one of debug, info, warn, error", errInvalidLogLevel, level)
+	}
+	opts := &slog.HandlerOptions{Level: l}
+	switch format {
+	case logFormatText:
+		return slog.NewTextHandler(w, opts), nil
+	case logFormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("%w %q, want %s or %s", errInvalidLogFormat, format, logFormatText, logFormatJSON)
+	}
+}
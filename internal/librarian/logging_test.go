@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/googleapis/librarian/internal/command"
+)
+
+func TestLogLevelFlag(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		command.Verbose = false
+	})
+	for _, test := range []struct {
+		name string
+		args []string
+		want slog.Level
+	}{
+		{"debug", []string{"librarian", "--log-level", "debug", "version"}, slog.LevelDebug},
+		{"warn", []string{"librarian", "--log-level", "warn", "version"}, slog.LevelWarn},
+		{"error uppercase", []string{"librarian", "--log-level", "ERROR", "version"}, slog.LevelError},
+		{"json format keeps info", []string{"librarian", "--log-format", "json", "version"}, slog.LevelInfo},
+		{"verbose is debug", []string{"librarian", "--verbose", "version"}, slog.LevelDebug},
+		{"verbose overrides level", []string{"librarian", "-v", "--log-level", "error", "version"}, slog.LevelDebug},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			slog.SetDefault(defaultLogger)
+			if err := Run(t.Context(), test.args...); err != nil {
+				t.Fatal(err)
+			}
+			handler := slog.Default().Handler()
+			if !handler.Enabled(t.Context(), test.want) {
+				t.Errorf("level %v is disabled, want enabled", test.want)
+			}
+			if handler.Enabled(t.Context(), test.want-1) {
+				t.Errorf("level %v is enabled, want disabled", test.want-1)
+			}
+		})
+	}
+}
+
+func TestLogLevelFlag_Error(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+	for _, test := range []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{"unknown level", []string{"librarian", "--log-level", "trace", "version"}, errInvalidLogLevel},
+		{"unknown format", []string{"librarian", "--log-format", "xml", "version"}, errInvalidLogFormat},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := Run(t.Context(), test.args...)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewLogHandler_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := newLogHandler(&buf, "info", logFormatJSON, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(handler)
+	logger.Debug("hidden")
+	logger.Info("generating", "library", "google-cloud-storage")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("log output is not a single JSON record: %v\n%s", err, buf.String())
+	}
+	for key, want := range map[string]string{
+		"level":   "INFO",
+		"msg":     "generating",
+		"library": "google-cloud-storage",
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %v, want %q", key, got[key], want)
+		}
+	}
+}
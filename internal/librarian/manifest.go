@@ -0,0 +1,130 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/googleapis/librarian/internal/config"
+)
+
+// fileManifestName is the name of the file, written to each library
+// directory, that lists the generated files.
+const fileManifestName = "MANIFEST.files.json"
+
+// fileManifest is the content of fileManifestName.
+type fileManifest struct {
+	// Library is the library name.
+	Library string `json:"library"`
+
+	// Files are the paths of the files in the library directory, relative to
+	// that directory, using forward slashes and sorted.
+	Files []string `json:"files"`
+}
+
+// writeFileManifest writes fileManifestName to the directory of lib, listing
+// every file generated in it.
+func writeFileManifest(cfg *config.Config, lib *config.Library) error {
+	dir, files, err := listLibraryFiles(cfg, lib)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(fileManifest{Library: lib.Name, Files: files}, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(filepath.Join(dir, fileManifestName), data, 0644)
+}
+
+// listLibraryFiles returns the directory of lib, as given by libraryDir, and
+// the sorted paths relative to it of the files generated there. The
+// directories of other configured libraries nested inside it are skipped, as
+// they have manifests of their own, and so are .git directories and the
+// paths in lib's keep list, which are not generated.
+func listLibraryFiles(cfg *config.Config, lib *config.Library) (string, []string, error) {
+	dir := filepath.Clean(libraryDir(cfg.Language, lib, cfg.Default))
+	nested := make(map[string]bool)
+	for _, other := range cfg.Libraries {
+		if other.Name == lib.Name {
+			continue
+		}
+		if otherDir := libraryDir(cfg.Language, other, cfg.Default); otherDir != "" {
+			nested[filepath.Clean(otherDir)] = true
+		}
+	}
+	kept, err := keptPaths(lib)
+	if err != nil {
+		return "", nil, err
+	}
+	files := []string{}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		path = filepath.Clean(path)
+		if path != dir && kept[path] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && (nested[path] || d.Name() == ".git") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == fileManifestName {
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("listing files for %q: %w", lib.Name, err)
+	}
+	slices.Sort(files)
+	return dir, files, nil
+}
+
+// keptPaths returns the paths matched by the keep list of lib, joined to its
+// output directory. Entries that match nothing are ignored.
+func keptPaths(lib *config.Library) (map[string]bool, error) {
+	kept := make(map[string]bool)
+	for _, k := range lib.Keep {
+		if !isKeepPattern(k) {
+			kept[filepath.Join(lib.Output, k)] = true
+			continue
+		}
+		matches, err := globKeep(lib.Output, k)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			kept[filepath.Join(lib.Output, m)] = true
+		}
+	}
+	return kept, nil
+}
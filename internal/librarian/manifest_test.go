@@ -0,0 +1,143 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+func TestGenerateCommand_FileManifest(t *testing.T) {
+	googleapisDir := createGoogleapisServiceConfigs(t, t.TempDir(), map[string]string{
+		"google/cloud/speech/v1": "speech_v1.yaml",
+	})
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Default.FileManifest = true
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+	}
+	if err := yaml.Write(librarianConfigPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(t.Context(), "librarian", "generate", "library-one"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "output1", fileManifestName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got fileManifest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := fileManifest{
+		Library: "library-one",
+		Files:   []string{"README.md", "STARTER.md", "VERSION"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListLibraryFiles(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		cfg       *config.Config
+		files     []string
+		wantDir   string
+		wantFiles []string
+	}{
+		{
+			name: "nested library and keep",
+			cfg: &config.Config{
+				Language: languageFake,
+				Libraries: []*config.Library{
+					{Name: "parent", Output: "parent", Keep: []string{"CHANGELOG.md", "docs/*.md"}},
+					{Name: "child", Output: "parent/child"},
+				},
+			},
+			files: []string{
+				"parent/README.md",
+				"parent/CHANGELOG.md",
+				"parent/docs/guide.md",
+				"parent/src/lib.rs",
+				"parent/" + fileManifestName,
+				"parent/child/README.md",
+			},
+			wantDir:   "parent",
+			wantFiles: []string{"README.md", "src/lib.rs"},
+		},
+		{
+			name: "go libraries share the output root",
+			cfg: &config.Config{
+				Language: languageGo,
+				Default:  &config.Default{Output: "."},
+				Libraries: []*config.Library{
+					{Name: "parent"},
+					{Name: "parent/child"},
+					{Name: "pubsub"},
+				},
+			},
+			files: []string{
+				".git/HEAD",
+				"go.work",
+				"parent/go.mod",
+				"parent/apiv1/client.go",
+				"parent/child/go.mod",
+				"pubsub/go.mod",
+			},
+			wantDir:   "parent",
+			wantFiles: []string{"apiv1/client.go", "go.mod"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			for _, path := range test.files {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			gotDir, gotFiles, err := listLibraryFiles(test.cfg, test.cfg.Libraries[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotDir != test.wantDir {
+				t.Errorf("got dir %q, want %q", gotDir, test.wantDir)
+			}
+			if diff := cmp.Diff(test.wantFiles, gotFiles); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
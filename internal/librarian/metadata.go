@@ -0,0 +1,91 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/librarian/python"
+	"github.com/urfave/cli/v3"
+)
+
+func metadataCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "metadata",
+		Usage:     "regenerate package metadata files of a client library",
+		UsageText: "librarian metadata [library] [--all]",
+		Description: `metadata refreshes the package metadata files of a library, such as
+setup.py and pyproject.toml for Python, from librarian.yaml without
+regenerating the library. The version, description and release level are
+taken from the library configuration.
+
+Examples:
+  librarian metadata <library>       # refresh metadata for one library
+  librarian metadata --all           # refresh metadata for all libraries`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "refresh metadata for all libraries",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			all := cmd.Bool("all")
+			libraryName := cmd.Args().First()
+			if !all && libraryName == "" {
+				return errMissingLibraryOrAllFlag
+			}
+			if all && libraryName != "" {
+				return errBothLibraryAndAllFlag
+			}
+			cfg, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+			return runMetadata(cfg, all, libraryName)
+		},
+	}
+}
+
+func runMetadata(cfg *config.Config, all bool, libraryName string) error {
+	libraries := cfg.Libraries
+	if !all {
+		lib, err := findLibrary(cfg, libraryName)
+		if err != nil {
+			return err
+		}
+		libraries = []*config.Library{lib}
+	}
+	for _, lib := range libraries {
+		library, err := applyDefaults(cfg.Language, lib, cfg.Default)
+		if err != nil {
+			return err
+		}
+		if err := updateMetadata(cfg.Language, library); err != nil {
+			return fmt.Errorf("library %q: %w", library.Name, err)
+		}
+	}
+	return nil
+}
+
+func updateMetadata(language string, library *config.Library) error {
+	switch language {
+	case languagePython:
+		return python.UpdateMetadata(library, library.Output)
+	default:
+		return fmt.Errorf("language %q does not support metadata", language)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+func TestMetadataCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Language = languagePython
+	cfg.Libraries = []*config.Library{
+		{
+			Name:         "google-cloud-secret-manager",
+			Output:       "packages/google-cloud-secret-manager",
+			Version:      "0.3.0",
+			ReleaseLevel: "preview",
+		},
+	}
+	if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), cfg); err != nil {
+		t.Fatal(err)
+	}
+	outdir := filepath.Join(tempDir, "packages", "google-cloud-secret-manager")
+	if err := os.MkdirAll(outdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(outdir, "pyproject.toml")
+	content := `[project]
+version = "0.2.0"
+classifiers = ["Development Status :: 5 - Production/Stable"]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(t.Context(), "librarian", "metadata", "google-cloud-secret-manager"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`version = "0.3.0"`, `"Development Status :: 4 - Beta"`} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("pyproject.toml does not contain %s:\n%s", want, got)
+		}
+	}
+}
+
+func TestMetadataCommand_Error(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{
+			name:    "no args",
+			args:    []string{"librarian", "metadata"},
+			wantErr: errMissingLibraryOrAllFlag,
+		},
+		{
+			name:    "library name and all flag",
+			args:    []string{"librarian", "metadata", "foo", "--all"},
+			wantErr: errBothLibraryAndAllFlag,
+		},
+		{
+			name:    "unknown library",
+			args:    []string{"librarian", "metadata", "foo"},
+			wantErr: ErrLibraryNotFound,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			t.Chdir(tempDir)
+			if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), sample.Config()); err != nil {
+				t.Fatal(err)
+			}
+			err := Run(t.Context(), test.args...)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
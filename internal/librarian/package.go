@@ -0,0 +1,212 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+// sourceDateEpochEnv is the environment variable, defined by
+// https://reproducible-builds.org/specs/source-date-epoch/, that sets the
+// modification time recorded for every archive entry.
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+var (
+	errMissingLibrary         = errors.New("must specify a library name")
+	errInvalidSourceDateEpoch = errors.New("invalid " + sourceDateEpochEnv)
+)
+
+func packageCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "package",
+		Usage:     "create a reproducible archive of a library's output",
+		UsageText: "librarian package <library> [-o <file>]",
+		Description: `package writes the generated files of a library to a gzip-compressed tar
+archive. For Go, whose libraries share the repository root, these are the files
+in the directory named after the library. Entries are sorted and written with
+fixed ownership, permissions and modification times, so packaging the same files
+twice produces byte-identical archives. The modification time is taken from
+SOURCE_DATE_EPOCH when it is set, and is the Unix epoch otherwise.
+
+Examples:
+  librarian package <library>                 # writes <library>.tar.gz
+  librarian package <library> -o out.tar.gz`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "o",
+				Usage: "write the archive to `file` (default <library>.tar.gz)",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			libraryName := cmd.Args().First()
+			if libraryName == "" {
+				return errMissingLibrary
+			}
+			cfg, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+			out := cmd.String("o")
+			if out == "" {
+				out = libraryName + ".tar.gz"
+			}
+			return runPackage(cfg, libraryName, out)
+		},
+	}
+}
+
+func runPackage(cfg *config.Config, libraryName, out string) error {
+	lib, err := findLibrary(cfg, libraryName)
+	if err != nil {
+		return err
+	}
+	library, err := applyDefaults(cfg.Language, lib, cfg.Default)
+	if err != nil {
+		return err
+	}
+	mtime, err := sourceDateEpoch()
+	if err != nil {
+		return err
+	}
+	dir, files, err := packageFiles(cfg, library)
+	if err != nil {
+		return err
+	}
+	// Never archive the archive itself when it is written inside the library.
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	absOut, err := filepath.Abs(out)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(absDir, absOut); err == nil {
+		files = slices.DeleteFunc(files, func(f string) bool { return f == filepath.ToSlash(rel) })
+	}
+	return writeArchive(out, dir, files, mtime)
+}
+
+// packageFiles returns the directory of lib and the sorted files in it that
+// belong in its archive: every file listed by the file manifest, and the
+// manifest itself if one has been written.
+func packageFiles(cfg *config.Config, lib *config.Library) (string, []string, error) {
+	dir, files, err := listLibraryFiles(cfg, lib)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, fileManifestName)); err == nil {
+		files = append(files, fileManifestName)
+		slices.Sort(files)
+	}
+	return dir, files, nil
+}
+
+// sourceDateEpoch returns the time set by sourceDateEpochEnv, or the Unix
+// epoch if it is not set.
+func sourceDateEpoch() (time.Time, error) {
+	value := os.Getenv(sourceDateEpochEnv)
+	if value == "" {
+		return time.Unix(0, 0).UTC(), nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", errInvalidSourceDateEpoch, value)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// writeArchive writes files, given relative to dir, to a gzip-compressed tar
+// archive at out. Ownership is cleared, permissions are normalized to 0644 or
+// 0755, and every entry has the modification time mtime.
+func writeArchive(out, dir string, files []string, mtime time.Time) (err error) {
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cerr := f.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+	gz := gzip.NewWriter(f)
+	gz.ModTime = mtime
+	tw := tar.NewWriter(gz)
+	for _, name := range files {
+		if err := addArchiveEntry(tw, dir, name, mtime); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addArchiveEntry(tw *tar.Writer, dir, name string, mtime time.Time) error {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name:    name,
+		ModTime: mtime,
+		Mode:    0644,
+	}
+	if info.Mode()&0111 != 0 {
+		hdr.Mode = 0755
+	}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		hdr.Typeflag = tar.TypeSymlink
+		hdr.Linkname = target
+		hdr.Mode = 0777
+		return tw.WriteHeader(hdr)
+	case info.Mode().IsRegular():
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = info.Size()
+	default:
+		return fmt.Errorf("cannot archive %s: unsupported file type %s", path, info.Mode().Type())
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(tw, src)
+	return err
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+func TestPackageCommand_Reproducible(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	t.Setenv(sourceDateEpochEnv, "1700000000")
+	if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), sample.Config()); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"README.md":      "# storage\n",
+		"src/lib.rs":     "pub fn f() {}\n",
+		"src/b/mod.rs":   "mod b;\n",
+		"Cargo.toml":     "[package]\n",
+		"scripts/run.sh": "#!/bin/sh\n",
+	} {
+		path := filepath.Join(sample.Lib1Output, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(sample.Lib1Output, "scripts/run.sh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(t.Context(), "librarian", "package", sample.Lib1Name, "-o", "first.tar.gz"); err != nil {
+		t.Fatal(err)
+	}
+	// Touch every file so only the contents, not the timestamps, match.
+	later := time.Now().Add(time.Hour)
+	if err := filepath.WalkDir(sample.Lib1Output, func(path string, _ os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(path, later, later)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(t.Context(), "librarian", "package", sample.Lib1Name, "-o", "second.tar.gz"); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := os.ReadFile("first.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := os.ReadFile("second.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatal("archives are not byte-identical")
+	}
+
+	type entry struct {
+		Name    string
+		Mode    int64
+		ModTime time.Time
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var got []entry
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, entry{Name: hdr.Name, Mode: hdr.Mode, ModTime: hdr.ModTime.UTC()})
+	}
+	mtime := time.Unix(1700000000, 0).UTC()
+	want := []entry{
+		{Name: "Cargo.toml", Mode: 0644, ModTime: mtime},
+		{Name: "README.md", Mode: 0644, ModTime: mtime},
+		{Name: "scripts/run.sh", Mode: 0755, ModTime: mtime},
+		{Name: "src/b/mod.rs", Mode: 0644, ModTime: mtime},
+		{Name: "src/lib.rs", Mode: 0644, ModTime: mtime},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunPackage_Go(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, path := range []string{
+		".git/HEAD",
+		"go.work",
+		"pubsub/go.mod",
+		"secretmanager/go.mod",
+		"secretmanager/apiv1/client.go",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{
+		Language: languageGo,
+		Default:  &config.Default{Output: "."},
+		Libraries: []*config.Library{
+			{Name: "pubsub"},
+			{Name: "secretmanager"},
+		},
+	}
+	if err := runPackage(cfg, "secretmanager", "secretmanager.tar.gz"); err != nil {
+		t.Fatal(err)
+	}
+	files, err := readArchive("secretmanager.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"apiv1/client.go", "go.mod"}
+	if diff := cmp.Diff(want, slices.Sorted(maps.Keys(files))); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPackageCommand_Error(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		args    []string
+		epoch   string
+		wantErr error
+	}{
+		{
+			name:    "no library",
+			args:    []string{"librarian", "package"},
+			wantErr: errMissingLibrary,
+		},
+		{
+			name:    "unknown library",
+			args:    []string{"librarian", "package", "foo"},
+			wantErr: ErrLibraryNotFound,
+		},
+		{
+			name:    "invalid source date epoch",
+			args:    []string{"librarian", "package", sample.Lib1Name},
+			epoch:   "yesterday",
+			wantErr: errInvalidSourceDateEpoch,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			t.Chdir(tempDir)
+			t.Setenv(sourceDateEpochEnv, test.epoch)
+			if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), sample.Config()); err != nil {
+				t.Fatal(err)
+			}
+			err := Run(t.Context(), test.args...)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
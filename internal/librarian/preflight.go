@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/command"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/git"
+)
+
+var (
+	errPreinstalledTool = errors.New("preinstalled tool is missing or not executable")
+	errToolInstaller    = errors.New("tool installer is missing or not executable")
+	errInvalidRootsPem  = errors.New("roots_pem has no PEM certificates")
+)
+
+// releasePreflight checks that the tools a release needs are available before
+// any work is done: every preinstalled path must be an executable file, and
+// the installer of every tool must be available to install it. The installer
+// may itself be preinstalled. If release.roots_pem is set, it must contain at
+// least one PEM certificate.
+func releasePreflight(release *config.Release) error {
+	if release == nil {
+		return nil
+	}
+	if err := validateRootsPem(release); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(release.Preinstalled)) {
+		path := release.Preinstalled[name]
+		if _, err := exec.LookPath(path); err != nil {
+			return fmt.Errorf("%w: %s at %q: install it or update release.preinstalled in librarian.yaml: %v",
+				errPreinstalledTool, name, path, err)
+		}
+	}
+	for _, installer := range slices.Sorted(maps.Keys(release.Tools)) {
+		var names []string
+		for _, tool := range release.Tools[installer] {
+			if tool.Name == "" {
+				return fmt.Errorf("%w: release.tools.%s has a tool with no name", errToolInstaller, installer)
+			}
+			names = append(names, tool.Name)
+		}
+		exe := command.GetExecutablePath(release.Preinstalled, installer)
+		if _, err := exec.LookPath(exe); err != nil {
+			return fmt.Errorf("%w: %s is needed to install %s: add it to PATH or to release.preinstalled in librarian.yaml: %v",
+				errToolInstaller, installer, strings.Join(names, ", "), err)
+		}
+	}
+	return nil
+}
+
+// validateRootsPem checks that release.roots_pem, if set, contains at least
+// one PEM certificate.
+func validateRootsPem(release *config.Release) error {
+	if release.RootsPem == "" {
+		return nil
+	}
+	data, err := os.ReadFile(release.RootsPem)
+	if err != nil {
+		return fmt.Errorf("reading release.roots_pem: %w", err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return fmt.Errorf("%w: %s", errInvalidRootsPem, release.RootsPem)
+	}
+	return nil
+}
+
+// logReleaseTarget logs the remote and branch that release targets, along
+// with the branch checked out in the repository, so that releasing from the
+// wrong branch is easy to spot.
+func logReleaseTarget(ctx context.Context, gitExe string, release *config.Release) error {
+	current, err := git.CurrentBranch(ctx, gitExe)
+	if err != nil {
+		return err
+	}
+	var remote, branch string
+	if release != nil {
+		remote, branch = release.Remote, release.Branch
+	}
+	slog.Info("release target", "remote", remote, "branch", branch, "current_branch", current)
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/testhelper"
+)
+
+func TestReleasePreflight(t *testing.T) {
+	dir := t.TempDir()
+	cargo := filepath.Join(dir, "cargo")
+	if err := os.WriteFile(cargo, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name    string
+		release *config.Release
+	}{
+		{
+			name: "no release config",
+		},
+		{
+			name: "all present",
+			release: &config.Release{
+				Preinstalled: map[string]string{"cargo": cargo},
+				Tools: map[string][]config.Tool{
+					"cargo": {{Name: "cargo-semver-checks", Version: "0.44.0"}},
+				},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if err := releasePreflight(test.release); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestReleasePreflight_Error(t *testing.T) {
+	dir := t.TempDir()
+	notExecutable := filepath.Join(dir, "cargo")
+	if err := os.WriteFile(notExecutable, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name    string
+		release *config.Release
+		wantErr error
+	}{
+		{
+			name: "missing preinstalled binary",
+			release: &config.Release{
+				Preinstalled: map[string]string{"git": filepath.Join(dir, "missing", "git")},
+			},
+			wantErr: errPreinstalledTool,
+		},
+		{
+			name: "preinstalled binary not executable",
+			release: &config.Release{
+				Preinstalled: map[string]string{"cargo": notExecutable},
+			},
+			wantErr: errPreinstalledTool,
+		},
+		{
+			name: "missing installer",
+			release: &config.Release{
+				Tools: map[string][]config.Tool{
+					"no-such-installer": {{Name: "tool", Version: "1.0.0"}},
+				},
+			},
+			wantErr: errToolInstaller,
+		},
+		{
+			name: "roots_pem without certificates",
+			release: &config.Release{
+				RootsPem: notExecutable,
+			},
+			wantErr: errInvalidRootsPem,
+		},
+		{
+			name: "missing roots_pem",
+			release: &config.Release{
+				RootsPem: filepath.Join(dir, "missing.pem"),
+			},
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name: "tool without name",
+			release: &config.Release{
+				Tools: map[string][]config.Tool{
+					"sh": {{Version: "1.0.0"}},
+				},
+			},
+			wantErr: errToolInstaller,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := releasePreflight(test.release)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateRootsPem(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "librarian test root"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootsPem := filepath.Join(t.TempDir(), "roots.pem")
+	if err := os.WriteFile(rootsPem, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validateRootsPem(&config.Release{RootsPem: rootsPem}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogReleaseTarget(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	testhelper.SetupRepo(t)
+	buf := captureLogs(t)
+	release := &config.Release{Remote: "upstream", Branch: "preview"}
+	if err := logReleaseTarget(t.Context(), "git", release); err != nil {
+		t.Fatal(err)
+	}
+	want := "msg=\"release target\" remote=upstream branch=preview current_branch=main\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// generateProgress logs the progress of generating a batch of libraries,
+// which may run in parallel.
+type generateProgress struct {
+	total     int
+	verbose   bool
+	start     time.Time
+	started   atomic.Int64
+	completed atomic.Int64
+	// now returns the current time; tests replace it to control durations.
+	now func() time.Time
+}
+
+func newGenerateProgress(total int, verbose bool) *generateProgress {
+	p := &generateProgress{total: total, verbose: verbose, now: time.Now}
+	p.start = p.now()
+	return p
+}
+
+// track logs that the library name is being generated, runs fn and, in
+// verbose mode, logs how long it took.
+func (p *generateProgress) track(name string, fn func() error) error {
+	i := p.started.Add(1)
+	slog.Info("generating library", "index", i, "total", p.total, "library", name)
+	start := p.now()
+	if err := fn(); err != nil {
+		return err
+	}
+	p.completed.Add(1)
+	if p.verbose {
+		slog.Info("generated library", "index", i, "total", p.total, "library", name, "elapsed", p.now().Sub(start))
+	}
+	return nil
+}
+
+// done logs how many libraries were generated, the total elapsed time and
+// the average time per generated library. Libraries whose generation failed
+// or never started are not counted, so the summary is accurate when
+// generation stops early.
+func (p *generateProgress) done() {
+	elapsed := p.now().Sub(p.start)
+	completed := p.completed.Load()
+	attrs := []any{"completed", completed, "total", p.total, "elapsed", elapsed}
+	if completed > 0 {
+		attrs = append(attrs, "average", elapsed/time.Duration(completed))
+	}
+	slog.Info("generated libraries", attrs...)
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// captureLogs redirects the default slog logger to a buffer for the rest of
+// the test. Times and levels are dropped so that lines are deterministic.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+	return &buf
+}
+
+func TestGenerateProgress(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		verbose bool
+		want    []string
+	}{
+		{
+			name: "default",
+			want: []string{
+				`msg="generating library" index=1 total=2 library=lib-a`,
+				`msg="generating library" index=2 total=2 library=lib-b`,
+				`msg="generated libraries" completed=2 total=2 elapsed=2s average=1s`,
+			},
+		},
+		{
+			name:    "verbose",
+			verbose: true,
+			want: []string{
+				`msg="generating library" index=1 total=2 library=lib-a`,
+				`msg="generated library" index=1 total=2 library=lib-a elapsed=1s`,
+				`msg="generating library" index=2 total=2 library=lib-b`,
+				`msg="generated library" index=2 total=2 library=lib-b elapsed=1s`,
+				`msg="generated libraries" completed=2 total=2 elapsed=2s average=1s`,
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			buf := captureLogs(t)
+			clock := time.Unix(0, 0)
+			p := newGenerateProgress(2, test.verbose)
+			p.now = func() time.Time { return clock }
+			p.start = clock
+			for _, name := range []string{"lib-a", "lib-b"} {
+				// Each library takes one second to generate.
+				if err := p.track(name, func() error {
+					clock = clock.Add(time.Second)
+					return nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p.done()
+			got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateProgress_Error(t *testing.T) {
+	buf := captureLogs(t)
+	clock := time.Unix(0, 0)
+	p := newGenerateProgress(3, true)
+	p.now = func() time.Time { return clock }
+	p.start = clock
+	if err := p.track("lib-a", func() error {
+		clock = clock.Add(time.Second)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	wantErr := errors.New("generation failed")
+	if err := p.track("lib-b", func() error {
+		clock = clock.Add(3 * time.Second)
+		return wantErr
+	}); !errors.Is(err, wantErr) {
+		t.Fatalf("want error %v, got %v", wantErr, err)
+	}
+	p.done()
+	want := []string{
+		`msg="generating library" index=1 total=3 library=lib-a`,
+		`msg="generated library" index=1 total=3 library=lib-a elapsed=1s`,
+		`msg="generating library" index=2 total=3 library=lib-b`,
+		`msg="generated libraries" completed=1 total=3 elapsed=4s average=4s`,
+	}
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateProgress_NoneCompleted(t *testing.T) {
+	buf := captureLogs(t)
+	p := newGenerateProgress(1, false)
+	if err := p.track("lib-a", func() error { return errors.New("generation failed") }); err == nil {
+		t.Fatal("expected an error")
+	}
+	p.done()
+	if strings.Contains(buf.String(), "average") {
+		t.Errorf("average logged with no library generated:\n%s", buf.String())
+	}
+}
+
+func TestGenerateAll_LogsProgress(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	apis := map[string]string{
+		"google/cloud/speech/v1":       "speech_v1.yaml",
+		"google/cloud/texttospeech/v1": "texttospeech_v1.yaml",
+		"google/cloud/translate/v3":    "translate_v3.yaml",
+	}
+	googleapisDir := createGoogleapisServiceConfigs(t, tempDir, apis)
+	configContent := fmt.Sprintf(`language: fake
+version: v0.1.0
+sources:
+  googleapis:
+    dir: %s
+libraries:
+  - name: library-one
+    output: output1
+    apis:
+      - path: google/cloud/speech/v1
+  - name: library-two
+    output: output2
+    apis:
+      - path: google/cloud/texttospeech/v1
+  - name: library-three
+    output: output3
+    apis:
+      - path: google/cloud/translate/v3
+`, googleapisDir)
+	if err := os.WriteFile(filepath.Join(tempDir, librarianConfigPath), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf := captureLogs(t)
+	if err := Run(t.Context(), "librarian", "generate", "--all"); err != nil {
+		t.Fatal(err)
+	}
+	logs := buf.String()
+	// Libraries are generated in parallel, so the order of names is not
+	// fixed, but each counter value must appear exactly once.
+	for i := 1; i <= 3; i++ {
+		counter := fmt.Sprintf(`msg="generating library" index=%d total=3 `, i)
+		if got := strings.Count(logs, counter); got != 1 {
+			t.Errorf("found %q %d times, want once:\n%s", counter, got, logs)
+		}
+	}
+	for _, name := range []string{"library-one", "library-two", "library-three"} {
+		if !strings.Contains(logs, "library="+name+"\n") {
+			t.Errorf("no progress logged for %s:\n%s", name, logs)
+		}
+	}
+	if !strings.Contains(logs, `msg="generated libraries" completed=3 total=3 `) {
+		t.Errorf("no summary logged:\n%s", logs)
+	}
+}
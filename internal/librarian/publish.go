@@ -17,12 +17,16 @@ package librarian
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
 	"github.com/googleapis/librarian/internal/git"
 	"github.com/googleapis/librarian/internal/librarian/rust"
-	"github.com/googleapis/librarian/internal/yaml"
 	"github.com/urfave/cli/v3"
 )
 
@@ -34,7 +38,7 @@ func publishCommand() *cli.Command {
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "execute",
-				Usage: "fully publish (default is to only perform a dry run)",
+				Usage: "fully publish (default is to only perform a dry run, printing the tags and release notes that would be created)",
 			},
 			&cli.StringFlag{
 				Name:  "library",
@@ -77,14 +81,21 @@ func legacyRustPublish(ctx context.Context, cfg *config.Config, cmd *cli.Command
 	return rust.Publish(ctx, cfg.Release, dryRun, dryRunKeepGoing, skipSemverChecks)
 }
 
+// defaultTagFormat is the tag format used when Default.TagFormat is not set.
+const defaultTagFormat = "{name}/v{version}"
+
 // publish implements the publish command. It is provided with the configuration
 // at HEAD, just to find the git executable to use, after which it finds the
 // release commit to publish. The configuration at the release commit is used
 // for all further operations (and the repo will be checked out at that commit).
 // The library flag allows a user to identify a specific release to publish, in
 // case of overlapping releases being performed. The execute flag says whether to
-// actually publish (true) or just perform a dry run (false).
+// actually publish (true) or just perform a dry run (false). A dry run prints
+// the tags and release notes that would be created for each library.
 func publish(ctx context.Context, cfg *config.Config, library string, execute bool) error {
+	if err := releasePreflight(cfg.Release); err != nil {
+		return err
+	}
 	gitExe := "git"
 	if cfg.Release != nil {
 		gitExe = command.GetExecutablePath(cfg.Release.Preinstalled, "git")
@@ -92,6 +103,9 @@ func publish(ctx context.Context, cfg *config.Config, library string, execute bo
 	if err := git.AssertGitStatusClean(ctx, gitExe); err != nil {
 		return err
 	}
+	if err := logReleaseTarget(ctx, gitExe, cfg.Release); err != nil {
+		return err
+	}
 	releaseCommitHash, err := findLatestReleaseCommitHash(ctx, gitExe, library)
 	if err != nil {
 		return err
@@ -100,7 +114,7 @@ func publish(ctx context.Context, cfg *config.Config, library string, execute bo
 		return err
 	}
 	// Reload the config after checking out the release commit.
-	cfg, err = yaml.Read[config.Config](librarianConfigPath)
+	cfg, err = readConfig(ctx)
 	if err != nil {
 		return err
 	}
@@ -109,17 +123,31 @@ func publish(ctx context.Context, cfg *config.Config, library string, execute bo
 	// findLatestReleaseCommitHash, but keeps the interface simple - and means
 	// that if we want to be able to specify the release commit directly, we
 	// can skip findLatestReleaseCommitHash entirely.)
-	cfgContentBeforeCommit, err := git.ShowFileAtRevision(ctx, gitExe, "HEAD~", librarianConfigPath)
+	cfgBeforeReleaseCommit, err := readConfigAtRevision(ctx, gitExe, "HEAD~", configPath(ctx))
 	if err != nil {
 		return err
 	}
-	cfgBeforeReleaseCommit, err := yaml.Unmarshal[config.Config]([]byte(cfgContentBeforeCommit))
+	releasedLibraries, err := findReleasedLibraries(cfgBeforeReleaseCommit, cfg)
 	if err != nil {
 		return err
 	}
-	librariesToPublish, err := findReleasedLibraries(cfgBeforeReleaseCommit, cfg)
-	if err != nil {
-		return err
+	var librariesToPublish []string
+	for _, name := range releasedLibraries {
+		lib, err := findLibrary(cfg, name)
+		if err != nil {
+			return err
+		}
+		if lib.SkipRelease {
+			slog.Info("skipping library with skip_release set", "library", name)
+			continue
+		}
+		librariesToPublish = append(librariesToPublish, name)
+	}
+
+	if !execute {
+		if err := previewRelease(ctx, os.Stdout, gitExe, cfgBeforeReleaseCommit, cfg, librariesToPublish); err != nil {
+			return err
+		}
 	}
 
 	switch cfg.Language {
@@ -129,3 +157,77 @@ func publish(ctx context.Context, cfg *config.Config, library string, execute bo
 		return fmt.Errorf("%q does not support publish", cfg.Language)
 	}
 }
+
+// previewRelease writes the tag and release notes that publishing each of the
+// named libraries would create to w. The release notes are the messages of the
+// commits affecting the library since the tag of its previous version, or of
+// all commits affecting the library if there is no such tag. No tags are
+// created and nothing is pushed. Commits that only change files matching
+// Release.IgnoredChanges are left out of the release notes.
+func previewRelease(ctx context.Context, w io.Writer, gitExe string, cfgBefore, cfg *config.Config, libraries []string) error {
+	now := time.Now()
+	for _, name := range libraries {
+		lib, err := findLibrary(cfg, name)
+		if err != nil {
+			return err
+		}
+		since := ""
+		if libBefore, err := findLibrary(cfgBefore, name); err == nil && libBefore.Version != "" {
+			since, err = previousReleaseTag(ctx, gitExe, cfg.Default, libBefore)
+			if err != nil {
+				return err
+			}
+		}
+		dir := libraryDir(cfg.Language, lib, cfg.Default)
+		if dir == "" {
+			dir = "."
+		}
+		messages, err := git.CommitMessagesForPathSince(ctx, gitExe, since, dir, ignoredChanges(cfg))
+		if err != nil {
+			return err
+		}
+		tag, err := releaseTag(cfg.Default, lib, now)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "tag: %s\n", tag)
+		fmt.Fprintf(w, "release notes for %s %s:\n", lib.Name, lib.Version)
+		for _, message := range messages {
+			fmt.Fprintf(w, "- %s\n", strings.SplitN(message, "\n", 2)[0])
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// previousReleaseTag returns the existing tag of lib at its current version, or
+// an empty string if there is none. If the tag format includes the date, the
+// most recent matching tag is returned.
+func previousReleaseTag(ctx context.Context, gitExe string, defaults *config.Default, lib *config.Library) (string, error) {
+	pattern, err := config.TagPattern(tagFormat(defaults), lib.Name, lib.Version)
+	if err != nil {
+		return "", err
+	}
+	tags, err := git.ListTags(ctx, gitExe, pattern)
+	if err != nil {
+		return "", err
+	}
+	if len(tags) == 0 {
+		return "", nil
+	}
+	return tags[len(tags)-1], nil
+}
+
+// releaseTag returns the git tag for the library at its current version,
+// released on date, using the configured tag format.
+func releaseTag(defaults *config.Default, lib *config.Library, date time.Time) (string, error) {
+	return config.FormatTag(tagFormat(defaults), lib.Name, lib.Version, date)
+}
+
+// tagFormat returns the configured tag format, or defaultTagFormat.
+func tagFormat(defaults *config.Default) string {
+	if defaults != nil && defaults.TagFormat != "" {
+		return defaults.TagFormat
+	}
+	return defaultTagFormat
+}
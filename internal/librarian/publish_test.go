@@ -15,11 +15,15 @@
 package librarian
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
 	"github.com/googleapis/librarian/internal/sample"
 	"github.com/googleapis/librarian/internal/testhelper"
@@ -54,6 +58,16 @@ func TestPublish(t *testing.T) {
 			execute: true,
 			want:    fmt.Sprintf("libraries=%s,%s; execute=true", sample.Lib1Name, sample.Lib2Name),
 		},
+		{
+			name: "publish Lib1Name (Lib2Name has skip_release set)",
+			setup: func(cfg *config.Config) {
+				cfg.Libraries[0].Version = "1.1.0"
+				cfg.Libraries[1].Version = "1.3.0"
+				cfg.Libraries[1].SkipRelease = true
+				writeConfigAndCommit(t, cfg)
+			},
+			want: fmt.Sprintf("libraries=%s; execute=false", sample.Lib1Name),
+		},
 		{
 			name: "publish Lib1Name (Lib2Name not released)",
 			setup: func(cfg *config.Config) {
@@ -182,3 +196,144 @@ func TestPublishCommand(t *testing.T) {
 		t.Errorf("mismatch in output (-want +got):\n%s", diff)
 	}
 }
+
+func TestReleaseTag(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		tagFormat string
+		lib       *config.Library
+		want      string
+	}{
+		{
+			name: "default format",
+			lib:  &config.Library{Name: sample.Lib1Name, Version: "1.1.0"},
+			want: sample.Lib1Name + "/v1.1.0",
+		},
+		{
+			name:      "name and version",
+			tagFormat: "{name}/v{version}",
+			lib:       &config.Library{Name: sample.Lib2Name, Version: "1.3.0"},
+			want:      sample.Lib2Name + "/v1.3.0",
+		},
+		{
+			name:      "version first",
+			tagFormat: "v{version}-{name}",
+			lib:       &config.Library{Name: sample.Lib1Name, Version: "2.0.0"},
+			want:      "v2.0.0-" + sample.Lib1Name,
+		},
+		{
+			name:      "major version and date",
+			tagFormat: "{name}/v{major}/{date}",
+			lib:       &config.Library{Name: sample.Lib1Name, Version: "2.1.0"},
+			want:      sample.Lib1Name + "/v2/2026-03-01",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			date := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+			got, err := releaseTag(&config.Default{TagFormat: test.tagFormat}, test.lib, date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPreviewRelease(t *testing.T) {
+	cfg := sample.Config()
+	cfg.Default.TagFormat = "{name}-v{version}"
+	cfg.Libraries[1].Version = "1.2.0"
+	testhelper.Setup(t, testhelper.SetupOptions{Config: cfg})
+	if err := command.Run(t.Context(), "git", "tag", sample.Lib1Name+"-v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range []struct {
+		dir, message string
+	}{
+		{sample.Lib1Output, "feat: add storage file"},
+		{sample.Lib2Output, "fix: add gax file\n\nWith a body."},
+	} {
+		if err := os.MkdirAll(change.dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(change.dir, "file.txt"), []byte(change.message), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "add", "."); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "commit", "-m", change.message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfgBefore := *cfg
+	cfgBefore.Libraries = []*config.Library{
+		{Name: sample.Lib1Name, Version: "1.0.0"},
+		{Name: sample.Lib2Name, Version: "1.2.0"},
+	}
+	cfg.Libraries[0].Version = "1.1.0"
+	cfg.Libraries[1].Version = "1.3.0"
+
+	var got bytes.Buffer
+	if err := previewRelease(t.Context(), &got, "git", &cfgBefore, cfg, []string{sample.Lib1Name, sample.Lib2Name}); err != nil {
+		t.Fatal(err)
+	}
+	// Lib2Name has no tag for its previous version, so its release notes
+	// include all commits affecting the library.
+	want := fmt.Sprintf(`tag: %[1]s-v1.1.0
+release notes for %[1]s 1.1.0:
+- feat: add storage file
+
+tag: %[2]s-v1.3.0
+release notes for %[2]s 1.3.0:
+- fix: add gax file
+- initial version
+
+`, sample.Lib1Name, sample.Lib2Name)
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPreviewRelease_DateTagFormat(t *testing.T) {
+	cfg := sample.Config()
+	cfg.Default.TagFormat = "{name}-v{version}-{date}"
+	testhelper.Setup(t, testhelper.SetupOptions{Config: cfg})
+	// The previous version was tagged on an earlier date, and tagged again
+	// later; the latest tag is used.
+	for _, tag := range []string{"2020-01-02", "2020-01-03"} {
+		if err := command.Run(t.Context(), "git", "tag", sample.Lib1Name+"-v1.0.0-"+tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(sample.Lib1Output, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sample.Lib1Output, "file.txt"), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := command.Run(t.Context(), "git", "add", "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := command.Run(t.Context(), "git", "commit", "-m", "feat: add storage file"); err != nil {
+		t.Fatal(err)
+	}
+	cfgBefore := *cfg
+	cfgBefore.Libraries = []*config.Library{{Name: sample.Lib1Name, Version: "1.0.0"}}
+	cfg.Libraries[0].Version = "1.1.0"
+
+	var got bytes.Buffer
+	if err := previewRelease(t.Context(), &got, "git", &cfgBefore, cfg, []string{sample.Lib1Name}); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf(`tag: %[1]s-v1.1.0-%[2]s
+release notes for %[1]s 1.1.0:
+- feat: add storage file
+
+`, sample.Lib1Name, time.Now().Format(time.DateOnly))
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
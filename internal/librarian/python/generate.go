@@ -17,17 +17,31 @@ package python
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/protoimports"
 	"github.com/googleapis/librarian/internal/repometadata"
 	"github.com/googleapis/librarian/internal/serviceconfig"
 )
 
+var (
+	errOptArgContainsComma = errors.New("generator option must not contain a comma")
+	errCompileCheck        = errors.New("generated Python code does not compile")
+)
+
 // Generate generates a Python client library.
 func Generate(ctx context.Context, library *config.Library, googleapisDir string) error {
 	if len(library.APIs) == 0 {
@@ -95,6 +109,20 @@ func Generate(ctx context.Context, library *config.Library, googleapisDir string
 		return fmt.Errorf("failed to cleanup after post processing: %w", err)
 	}
 
+	if err := UpdateMetadata(library, outdir); err != nil {
+		return fmt.Errorf("failed to update package metadata: %w", err)
+	}
+
+	if err := writeConstraints(library, outdir); err != nil {
+		return fmt.Errorf("failed to write constraints file: %w", err)
+	}
+
+	if library.Python != nil && library.Python.CompileCheck {
+		if err := compileCheck(ctx, outdir); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -125,6 +153,9 @@ func generateAPI(ctx context.Context, api *config.API, library *config.Library,
 	if len(protos) == 0 {
 		return fmt.Errorf("no protos found in api %q", api.Path)
 	}
+	if err := protoimports.Check([]string{googleapisDir}, protos); err != nil {
+		return err
+	}
 
 	// We want the proto filenames to be relative to googleapisDir
 	for index, protoFile := range protos {
@@ -166,6 +197,15 @@ func createProtocOptions(ch *config.API, library *config.Library, googleapisDir,
 			opts = append(opts, apiOptArgs...)
 		}
 	}
+	// All options are passed in a single comma-separated --python_gapic_opt
+	// flag. protoc joins repeated --python_gapic_opt flags with commas too, so
+	// an option containing a comma would be split into several options
+	// whichever form is used.
+	for _, opt := range opts {
+		if strings.Contains(opt, ",") {
+			return nil, fmt.Errorf("%w: %q", errOptArgContainsComma, opt)
+		}
+	}
 	restNumericEnums := true
 	addTransport := library.Transport != ""
 	for _, opt := range opts {
@@ -297,6 +337,66 @@ func cleanUpFilesAfterPostProcessing(repoRoot string) error {
 	return nil
 }
 
+// writeConstraints writes constraints.txt to outdir, pinning each package in
+// library.Python.Constraints to its configured version. Nothing is written if
+// no constraints are configured.
+func writeConstraints(library *config.Library, outdir string) error {
+	if library.Python == nil || len(library.Python.Constraints) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("# Generated by librarian from librarian.yaml. DO NOT EDIT.\n")
+	for _, name := range slices.Sorted(maps.Keys(library.Python.Constraints)) {
+		fmt.Fprintf(&b, "%s==%s\n", name, library.Python.Constraints[name])
+	}
+	return os.WriteFile(filepath.Join(outdir, "constraints.txt"), []byte(b.String()), 0644)
+}
+
+// compileCheckBatchSize is the maximum number of files passed to a single
+// py_compile invocation, keeping the command line well below ARG_MAX for
+// large libraries.
+var compileCheckBatchSize = 500
+
+// compileCheck byte-compiles every .py file in outdir with py_compile, as a
+// cheap check that the generated code has no syntax errors. The check is
+// skipped if python3 is not on the PATH. Compiled files are written to a
+// temporary directory rather than to __pycache__ directories in outdir.
+func compileCheck(ctx context.Context, outdir string) error {
+	if _, err := exec.LookPath("python3"); err != nil {
+		slog.Warn("skipping Python compile check: python3 not found")
+		return nil
+	}
+	var files []string
+	err := filepath.WalkDir(outdir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && filepath.Ext(path) == ".py" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	cacheDir, err := os.MkdirTemp("", "librarian-pycache-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(cacheDir)
+	env := map[string]string{"PYTHONPYCACHEPREFIX": cacheDir}
+	for batch := range slices.Chunk(files, compileCheckBatchSize) {
+		args := append([]string{"-m", "py_compile"}, batch...)
+		if err := command.RunWithEnv(ctx, env, "python3", args...); err != nil {
+			return fmt.Errorf("%w: %w", errCompileCheck, err)
+		}
+	}
+	return nil
+}
+
 // DefaultOutputByName derives an output path from a library name and a default
 // output directory. Currently this just assumes each library is a directory
 // directly underneath the default output directory.
@@ -304,6 +404,71 @@ func DefaultOutputByName(name, defaultOutput string) string {
 	return filepath.Join(defaultOutput, name)
 }
 
+// DeriveAPIPath derives an API path from a library name, using the API
+// allowlist to find the versioned path when possible. Stable versions are
+// preferred over pre-release versions, and higher major versions over lower
+// ones. For example: "google-cloud-secretmanager" ->
+// "google/cloud/secretmanager/v1". If no API available to Python matches, the
+// name with "-" replaced by "/" is returned.
+func DeriveAPIPath(name string) string {
+	base := strings.ReplaceAll(name, "-", "/")
+	var (
+		best        string
+		bestVersion apiVersion
+	)
+	for _, api := range serviceconfig.APIs {
+		if len(api.Languages) > 0 && !slices.Contains(api.Languages, "python") {
+			continue
+		}
+		if api.Path == base {
+			return base
+		}
+		if path.Dir(api.Path) != base {
+			continue
+		}
+		v, ok := parseAPIVersion(path.Base(api.Path))
+		if !ok {
+			continue
+		}
+		if best == "" || v.after(bestVersion) {
+			best, bestVersion = api.Path, v
+		}
+	}
+	if best == "" {
+		return base
+	}
+	return best
+}
+
+// apiVersion is a parsed API version such as "v1" or "v2beta1".
+type apiVersion struct {
+	major  int
+	suffix string
+}
+
+func parseAPIVersion(s string) (apiVersion, bool) {
+	if len(s) < 2 || s[0] != 'v' {
+		return apiVersion{}, false
+	}
+	digits := strings.TrimLeft(s[1:], "0123456789")
+	major, err := strconv.Atoi(s[1 : len(s)-len(digits)])
+	if err != nil {
+		return apiVersion{}, false
+	}
+	return apiVersion{major: major, suffix: digits}, true
+}
+
+// after reports whether v is preferred over o.
+func (v apiVersion) after(o apiVersion) bool {
+	if stable, otherStable := v.suffix == "", o.suffix == ""; stable != otherStable {
+		return stable
+	}
+	if v.major != o.major {
+		return v.major > o.major
+	}
+	return v.suffix > o.suffix
+}
+
 // DefaultLibraryName derives a library name from an API path by stripping
 // the version suffix and replacing "/" with "-".
 // For example: "google/cloud/secretmanager/v1" ->
@@ -15,10 +15,12 @@
 package python
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -188,6 +190,29 @@ func TestCreateProtocOptions(t *testing.T) {
 				"--python_gapic_opt=metadata,transport=rest,rest-numeric-enums,retry-config=google/cloud/secretmanager/v1/secretmanager_grpc_service_config.json,service-yaml=google/cloud/secretmanager/v1/secretmanager_v1.yaml",
 			},
 		},
+		{
+			name: "opt args are joined into a single option",
+			api:  &config.API{Path: "google/cloud/secretmanager/v1"},
+			library: &config.Library{
+				Python: &config.PythonPackage{
+					OptArgs: []string{"warehouse-package-name=google-cloud-secret-manager", "python-gapic-namespace=google.cloud"},
+				},
+			},
+			expected: []string{
+				"--python_gapic_out=staging",
+				"--python_gapic_opt=metadata,warehouse-package-name=google-cloud-secret-manager,python-gapic-namespace=google.cloud,rest-numeric-enums,retry-config=google/cloud/secretmanager/v1/secretmanager_grpc_service_config.json,service-yaml=google/cloud/secretmanager/v1/secretmanager_v1.yaml",
+			},
+		},
+		{
+			name: "opt arg containing a comma",
+			api:  &config.API{Path: "google/cloud/secretmanager/v1"},
+			library: &config.Library{
+				Python: &config.PythonPackage{
+					OptArgs: []string{"python-gapic-name=a,b"},
+				},
+			},
+			wantErr: true,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			got, err := createProtocOptions(test.api, test.library, googleapisDir, "staging")
@@ -460,6 +485,34 @@ func TestDefaultLibraryName(t *testing.T) {
 	}
 }
 
+func TestDeriveAPIPath(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		want string
+	}{
+		// Stable v1 is preferred over v1beta2.
+		{"google-cloud-secretmanager", "google/cloud/secretmanager/v1"},
+		// The highest stable major version is preferred.
+		{"google-cloud-functions", "google/cloud/functions/v2"},
+		// Python-only APIs.
+		{"google-ads-admanager", "google/ads/admanager/v1"},
+		{"google-ai-generativelanguage", "google/ai/generativelanguage/v1"},
+		// Only pre-release versions; the latest is preferred.
+		{"google-analytics-admin", "google/analytics/admin/v1beta"},
+		// Unversioned API path.
+		{"google-api", "google/api"},
+		// Not in the allowlist.
+		{"google-cloud-notanapi", "google/cloud/notanapi"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := DeriveAPIPath(test.name)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func requirePythonModule(t *testing.T, module string) {
 	t.Helper()
 	cmd := exec.Command("python3", "-c", fmt.Sprintf("import %s", module))
@@ -467,3 +520,128 @@ func requirePythonModule(t *testing.T, module string) {
 		t.Skipf("skipping test because Python module %s is not installed", module)
 	}
 }
+
+func TestWriteConstraints(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		library *config.Library
+		want    string
+	}{
+		{
+			name: "constraints",
+			library: &config.Library{
+				Name: "google-cloud-secret-manager",
+				Python: &config.PythonPackage{
+					Constraints: map[string]string{
+						"protobuf": "5.29.3",
+						"grpcio":   "1.70.0",
+					},
+				},
+			},
+			want: `# Generated by librarian from librarian.yaml. DO NOT EDIT.
+grpcio==1.70.0
+protobuf==5.29.3
+`,
+		},
+		{
+			name:    "no python config",
+			library: &config.Library{Name: "google-cloud-secret-manager"},
+		},
+		{
+			name: "no constraints",
+			library: &config.Library{
+				Name:   "google-cloud-secret-manager",
+				Python: &config.PythonPackage{},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			outdir := t.TempDir()
+			if err := writeConstraints(test.library, outdir); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(filepath.Join(outdir, "constraints.txt"))
+			if test.want == "" {
+				if !os.IsNotExist(err) {
+					t.Errorf("constraints.txt should not exist, got error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, string(got)); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompileCheck(t *testing.T) {
+	testhelper.RequireCommand(t, "python3")
+	for _, test := range []struct {
+		name      string
+		batchSize int
+		files     map[string]string
+		wantErr   error
+	}{
+		{
+			name: "valid",
+			files: map[string]string{
+				"google/cloud/secretmanager/__init__.py": "from .client import Client\n",
+				"google/cloud/secretmanager/client.py":   "class Client:\n    pass\n",
+			},
+		},
+		{
+			name: "invalid",
+			files: map[string]string{
+				"google/cloud/secretmanager/__init__.py": "from .client import Client\n",
+				"google/cloud/secretmanager/client.py":   "class Client\n    pass\n",
+			},
+			wantErr: errCompileCheck,
+		},
+		{
+			name:      "invalid in a later batch",
+			batchSize: 1,
+			files: map[string]string{
+				"google/cloud/secretmanager/__init__.py": "from .client import Client\n",
+				"google/cloud/secretmanager/client.py":   "class Client\n    pass\n",
+				"google/cloud/secretmanager/types.py":    "class Secret:\n    pass\n",
+			},
+			wantErr: errCompileCheck,
+		},
+		{
+			name: "no python files",
+			files: map[string]string{
+				"README.rst": "Secret Manager\n",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.batchSize != 0 {
+				defer func(size int) { compileCheckBatchSize = size }(compileCheckBatchSize)
+				compileCheckBatchSize = test.batchSize
+			}
+			outdir := t.TempDir()
+			for name, content := range test.files {
+				path := filepath.Join(outdir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := compileCheck(t.Context(), outdir)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("compileCheck() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "client.py") {
+				t.Errorf("error %q does not name the invalid file", err)
+			}
+			if _, err := os.Stat(filepath.Join(outdir, "google", "cloud", "secretmanager", "__pycache__")); err == nil {
+				t.Errorf("compileCheck() wrote __pycache__ to the output directory")
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package python
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+)
+
+// developmentStatus maps a release level to its trove classifier.
+var developmentStatus = map[string]string{
+	"preview": "Development Status :: 4 - Beta",
+	"stable":  "Development Status :: 5 - Production/Stable",
+}
+
+var (
+	setupAssignment       = regexp.MustCompile(`^(version|description|release_status) = ["'].*["']\s*$`)
+	pyprojectAssignment   = regexp.MustCompile(`^(version|description)\s*=\s*".*"\s*$`)
+	developmentClassifier = regexp.MustCompile(`"Development Status :: [^"]*"`)
+)
+
+// UpdateMetadata refreshes the version, description and development status
+// classifier in the setup.py and pyproject.toml files in outdir from the
+// library configuration. Fields that are not set in the configuration are
+// left unchanged, as are files that do not exist.
+//
+// In setup.py, only top-level assignments of string literals to version,
+// description and release_status are rewritten. In pyproject.toml, the
+// version and description keys and the "Development Status" classifier of
+// the [project] table are rewritten.
+//
+// UpdateMetadata is intended to run after every generation: the post
+// processor renders setup.py and pyproject.toml from templates, so values
+// from the library configuration must be applied again each time. Files are
+// only written when their contents change.
+func UpdateMetadata(library *config.Library, outdir string) error {
+	values := map[string]string{
+		"version":        library.Version,
+		"description":    library.DescriptionOverride,
+		"release_status": developmentStatus[library.ReleaseLevel],
+	}
+	if err := rewriteFile(filepath.Join(outdir, "setup.py"), func(contents string) string {
+		return updateSetupPy(contents, values)
+	}); err != nil {
+		return err
+	}
+	return rewriteFile(filepath.Join(outdir, "pyproject.toml"), func(contents string) string {
+		return updatePyproject(contents, values)
+	})
+}
+
+// rewriteFile replaces the contents of path with the result of update. The
+// file is not written if it does not exist or its contents are unchanged.
+func rewriteFile(path string, update func(string) string) error {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	updated := update(string(contents))
+	if updated == string(contents) {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func updateSetupPy(contents string, values map[string]string) string {
+	lines := strings.Split(contents, "\n")
+	for i, line := range lines {
+		m := setupAssignment.FindStringSubmatch(line)
+		if m == nil || values[m[1]] == "" {
+			continue
+		}
+		lines[i] = fmt.Sprintf("%s = %s", m[1], pythonString(values[m[1]]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func updatePyproject(contents string, values map[string]string) string {
+	lines := strings.Split(contents, "\n")
+	inProject := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			inProject = trimmed == "[project]"
+			continue
+		}
+		if !inProject {
+			continue
+		}
+		if m := pyprojectAssignment.FindStringSubmatch(line); m != nil && values[m[1]] != "" {
+			lines[i] = fmt.Sprintf("%s = %s", m[1], tomlString(values[m[1]]))
+			continue
+		}
+		if status := values["release_status"]; status != "" {
+			lines[i] = developmentClassifier.ReplaceAllLiteralString(line, tomlString(status))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pythonString returns s as a double-quoted Python string literal.
+func pythonString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\', '"':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\x%02x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// tomlString returns s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\', '"':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package python
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+)
+
+func TestUpdateMetadata(t *testing.T) {
+	library := &config.Library{
+		Name:                "google-cloud-secret-manager",
+		Version:             "0.3.0",
+		DescriptionOverride: "Secret Manager API client library",
+		ReleaseLevel:        "preview",
+	}
+	for _, test := range []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{
+			name: "setup.py",
+			file: "setup.py",
+			content: `import setuptools
+
+name = "google-cloud-secret-manager"
+description = "Old description"
+version = "0.2.0"
+release_status = "Development Status :: 5 - Production/Stable"
+
+if version[0] == "0":
+    release_status = "Development Status :: 5 - Production/Stable"
+`,
+			want: `import setuptools
+
+name = "google-cloud-secret-manager"
+description = "Secret Manager API client library"
+version = "0.3.0"
+release_status = "Development Status :: 4 - Beta"
+
+if version[0] == "0":
+    release_status = "Development Status :: 5 - Production/Stable"
+`,
+		},
+		{
+			name: "pyproject.toml",
+			file: "pyproject.toml",
+			content: `[build-system]
+requires = ["setuptools"]
+
+[project]
+name = "google-cloud-secret-manager"
+version = "0.2.0"
+description = "Old description"
+classifiers = [
+  "Development Status :: 5 - Production/Stable",
+  "Programming Language :: Python",
+]
+
+[tool.example]
+version = "1.0.0"
+`,
+			want: `[build-system]
+requires = ["setuptools"]
+
+[project]
+name = "google-cloud-secret-manager"
+version = "0.3.0"
+description = "Secret Manager API client library"
+classifiers = [
+  "Development Status :: 4 - Beta",
+  "Programming Language :: Python",
+]
+
+[tool.example]
+version = "1.0.0"
+`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			outdir := t.TempDir()
+			path := filepath.Join(outdir, test.file)
+			if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := UpdateMetadata(library, outdir); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, string(got)); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateMetadata_UnsetFieldsUnchanged(t *testing.T) {
+	outdir := t.TempDir()
+	path := filepath.Join(outdir, "pyproject.toml")
+	content := `[project]
+version = "0.2.0"
+description = "Old description"
+classifiers = ["Development Status :: 5 - Production/Stable"]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateMetadata(&config.Library{Name: "google-cloud-secret-manager"}, outdir); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(content, string(got)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPythonString(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{`plain`, `"plain"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\path`, `"C:\\path"`},
+		{"two\nlines", `"two\nlines"`},
+		{"bell\a", `"bell\x07"`},
+		{"Café API", `"Café API"`},
+	} {
+		if got := pythonString(test.in); got != test.want {
+			t.Errorf("pythonString(%q) = %s, want %s", test.in, got, test.want)
+		}
+	}
+}
+
+func TestTOMLString(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{`plain`, `"plain"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\path`, `"C:\\path"`},
+		{"two\nlines", `"two\nlines"`},
+		{"bell\a", `"bell\u0007"`},
+		{"Café API", `"Café API"`},
+	} {
+		if got := tomlString(test.in); got != test.want {
+			t.Errorf("tomlString(%q) = %s, want %s", test.in, got, test.want)
+		}
+	}
+}
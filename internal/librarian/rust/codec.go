@@ -16,6 +16,7 @@ package rust
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/googleapis/librarian/internal/config"
@@ -224,6 +225,16 @@ func formatPackageDependency(dep *config.RustPackageDependency) string {
 
 func moduleToSidekickConfig(library *config.Library, module *config.RustModule, sources *Sources) (*sidekickconfig.Config, error) {
 	source := addLibraryRoots(library, sources)
+	for key, dir := range module.ModuleRoots {
+		// Like the library-level sources, module roots are passed to the
+		// generator as absolute paths; relative ones are resolved against
+		// the working directory, which holds librarian.yaml.
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("module root %q: %w", key, err)
+		}
+		source[key] = abs
+	}
 	if len(module.IncludedIds) > 0 {
 		source["included-ids"] = strings.Join(module.IncludedIds, ",")
 	}
@@ -234,7 +245,7 @@ func moduleToSidekickConfig(library *config.Library, module *config.RustModule,
 		source["include-list"] = module.IncludeList
 	}
 	if module.Source != "" && source["roots"] == "googleapis" {
-		api, err := serviceconfig.Find(sources.Googleapis, module.Source)
+		api, err := serviceconfig.Find(source["googleapis-root"], module.Source)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find service config for %q: %w", module.Source, err)
 		}
@@ -333,19 +344,10 @@ func addLibraryRoots(library *config.Library, sources *Sources) map[string]strin
 		source["roots"] = "googleapis"
 	} else {
 		source["roots"] = strings.Join(library.Roots, ",")
-		rootMap := map[string]struct {
-			path string
-			key  string
-		}{
-			"googleapis":   {path: sources.Googleapis, key: "googleapis-root"},
-			"discovery":    {path: sources.Discovery, key: "discovery-root"},
-			"showcase":     {path: sources.Showcase, key: "showcase-root"},
-			"protobuf-src": {path: sources.ProtobufSrc, key: "protobuf-src-root"},
-			"conformance":  {path: sources.Conformance, key: "conformance-root"},
-		}
+		dirs := sources.Dirs()
 		for _, root := range library.Roots {
-			if r, ok := rootMap[root]; ok && r.path != "" {
-				source[r.key] = r.path
+			if dir, ok := dirs[root]; ok {
+				source[root+"-root"] = dir
 			}
 		}
 	}
@@ -128,6 +128,7 @@ func TestLibraryToSidekickConfig(t *testing.T) {
 				Rust: &config.RustCrate{
 					RustDefault: config.RustDefault{
 						DisabledRustdocWarnings: []string{"broken_intra_doc_links"},
+						DisabledClippyWarnings:  []string{"too_many_arguments"},
 						GenerateSetterSamples:   "true",
 						GenerateRpcSamples:      "true",
 					},
@@ -137,7 +138,6 @@ func TestLibraryToSidekickConfig(t *testing.T) {
 					DetailedTracingAttributes: true,
 					HasVeneer:                 true,
 					RoutingRequired:           true,
-					DisabledClippyWarnings:    []string{"too_many_arguments"},
 					DefaultFeatures:           []string{"default-feature"},
 					TemplateOverride:          "custom-template",
 				},
@@ -767,6 +767,54 @@ func TestModuleToSidekickConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with module roots override",
+			library: &config.Library{
+				Name: "google-cloud-example",
+				Rust: &config.RustCrate{
+					Modules: []*config.RustModule{
+						{
+							RootName: "discovery-root",
+							ModuleRoots: map[string]string{
+								"discovery-root": "/alternate/discovery",
+							},
+						},
+					},
+				},
+				Roots: []string{"googleapis", "discovery"},
+			},
+			want: &sidekickconfig.Config{
+				Source: map[string]string{
+					"discovery-root":  "/alternate/discovery",
+					"googleapis-root": absPath(t, googleapisRoot),
+					"roots":           "googleapis,discovery",
+				},
+			},
+		},
+		{
+			name: "with relative module roots override",
+			library: &config.Library{
+				Name: "google-cloud-example",
+				Rust: &config.RustCrate{
+					Modules: []*config.RustModule{
+						{
+							RootName: "discovery-root",
+							ModuleRoots: map[string]string{
+								"discovery-root": "alternate/discovery",
+							},
+						},
+					},
+				},
+				Roots: []string{"googleapis", "discovery"},
+			},
+			want: &sidekickconfig.Config{
+				Source: map[string]string{
+					"discovery-root":  absPath(t, "alternate/discovery"),
+					"googleapis-root": absPath(t, googleapisRoot),
+					"roots":           "googleapis,discovery",
+				},
+			},
+		},
 		{
 			name: "with conformance as module source",
 			library: &config.Library{
@@ -995,6 +1043,7 @@ func TestBuildCodec(t *testing.T) {
 						GenerateSetterSamples:   "true",
 						GenerateRpcSamples:      "true",
 						DisabledRustdocWarnings: []string{"warning1", "warning2"},
+						DisabledClippyWarnings:  []string{"clippy1", "clippy2"},
 						PackageDependencies: []*config.RustPackageDependency{
 							{
 								Name:    "dep1",
@@ -1017,7 +1066,6 @@ func TestBuildCodec(t *testing.T) {
 					RoutingRequired:           true,
 					NameOverrides:             "foo=bar",
 					DefaultFeatures:           []string{"feature1", "feature2"},
-					DisabledClippyWarnings:    []string{"clippy1", "clippy2"},
 				},
 			},
 			want: map[string]string{
@@ -16,19 +16,24 @@ package rust
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/api"
 	"github.com/googleapis/librarian/internal/sidekick/parser"
 	sidekickrust "github.com/googleapis/librarian/internal/sidekick/rust"
 	"github.com/googleapis/librarian/internal/sidekick/rust_prost"
 )
 
+var errUnknownIDs = errors.New("included or skipped IDs match nothing in the API")
+
 // Sources contains the directory paths for source repositories used by
 // sidekick.
 type Sources struct {
@@ -39,6 +44,25 @@ type Sources struct {
 	Showcase    string
 }
 
+// Dirs returns the directory of each configured source, keyed by the root
+// name used in Library.Roots, such as "googleapis" or "protobuf-src". Sources
+// without a directory are omitted.
+func (s *Sources) Dirs() map[string]string {
+	dirs := make(map[string]string)
+	for root, dir := range map[string]string{
+		"conformance":  s.Conformance,
+		"discovery":    s.Discovery,
+		"googleapis":   s.Googleapis,
+		"protobuf-src": s.ProtobufSrc,
+		"showcase":     s.Showcase,
+	} {
+		if dir != "" {
+			dirs[root] = dir
+		}
+	}
+	return dirs
+}
+
 // Generate generates a Rust client library.
 func Generate(ctx context.Context, library *config.Library, sources *Sources) error {
 	if library.Veneer {
@@ -56,6 +80,11 @@ func Generate(ctx context.Context, library *config.Library, sources *Sources) er
 	if err != nil {
 		return err
 	}
+	if library.Rust != nil {
+		if err := validateIDs(model, nil, library.Rust.SkippedIds); err != nil {
+			return err
+		}
+	}
 	exists := true
 	if _, err := os.Stat(library.Output); err != nil {
 		if !os.IsNotExist(err) {
@@ -78,8 +107,13 @@ func Generate(ctx context.Context, library *config.Library, sources *Sources) er
 	return nil
 }
 
-// UpdateWorkspace updates dependencies for the entire Rust workspace.
-func UpdateWorkspace(ctx context.Context) error {
+// UpdateWorkspace adds crates to the members of the Rust workspace, keeping
+// the list sorted and free of duplicates, and then updates dependencies for
+// the entire workspace.
+func UpdateWorkspace(ctx context.Context, crates []string) error {
+	if err := updateWorkspaceMembers("Cargo.toml", crates); err != nil {
+		return err
+	}
 	return command.Run(ctx, "cargo", "update", "--workspace")
 }
 
@@ -109,6 +143,9 @@ func generateVeneer(ctx context.Context, library *config.Library, sources *Sourc
 		if err != nil {
 			return fmt.Errorf("module %q: %w", module.Output, err)
 		}
+		if err := validateIDs(model, module.IncludedIds, module.SkippedIds); err != nil {
+			return fmt.Errorf("module %q: %w", module.Output, err)
+		}
 		switch sidekickConfig.General.Language {
 		case "rust":
 			err = sidekickrust.Generate(ctx, model, module.Output, sidekickConfig.General.SpecificationFormat, sidekickConfig.Codec)
@@ -126,6 +163,36 @@ func generateVeneer(ctx context.Context, library *config.Library, sources *Sourc
 	return nil
 }
 
+// validateIDs returns an error listing the included and skipped IDs that do
+// not name a service, method, message or enum in model. Such IDs are usually
+// typos, which would otherwise silently include or skip nothing.
+func validateIDs(model *api.API, includedIDs, skippedIDs []string) error {
+	var unknown []string
+	for _, id := range append(slices.Clone(includedIDs), skippedIDs...) {
+		if !knownID(model.State, id) {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errUnknownIDs, strings.Join(unknown, ", "))
+}
+
+func knownID(state *api.APIState, id string) bool {
+	if _, ok := state.ServiceByID[id]; ok {
+		return true
+	}
+	if _, ok := state.MethodByID[id]; ok {
+		return true
+	}
+	if _, ok := state.MessageByID[id]; ok {
+		return true
+	}
+	_, ok := state.EnumByID[id]
+	return ok
+}
+
 // Keep returns the list of files to preserve when cleaning the output directory.
 func Keep(library *config.Library) ([]string, error) {
 	if !library.Veneer {
@@ -16,6 +16,7 @@ package rust
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"sort"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sidekick/api"
 	"github.com/googleapis/librarian/internal/testhelper"
 )
 
@@ -392,3 +394,50 @@ func TestFindModuleByOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateIDs(t *testing.T) {
+	method := &api.Method{Name: "GetSecret", ID: ".test.v1.SecretManager.GetSecret"}
+	service := &api.Service{Name: "SecretManager", ID: ".test.v1.SecretManager", Methods: []*api.Method{method}}
+	message := &api.Message{Name: "Secret", ID: ".test.v1.Secret"}
+	enum := &api.Enum{Name: "State", ID: ".test.v1.State"}
+	model := api.NewTestAPI([]*api.Message{message}, []*api.Enum{enum}, []*api.Service{service})
+
+	for _, test := range []struct {
+		name        string
+		included    []string
+		skipped     []string
+		wantUnknown string
+	}{
+		{
+			name:     "valid IDs",
+			included: []string{".test.v1.SecretManager", ".test.v1.Secret"},
+			skipped:  []string{".test.v1.SecretManager.GetSecret", ".test.v1.State"},
+		},
+		{
+			name:        "bogus included ID",
+			included:    []string{".test.v1.Secret", ".test.v1.Secrett"},
+			wantUnknown: ".test.v1.Secrett",
+		},
+		{
+			name:        "bogus skipped ID",
+			skipped:     []string{".test.v1.NoSuchService"},
+			wantUnknown: ".test.v1.NoSuchService",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateIDs(model, test.included, test.skipped)
+			if test.wantUnknown == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, errUnknownIDs) {
+				t.Fatalf("validateIDs() error = %v, want %v", err, errUnknownIDs)
+			}
+			if want := ": " + test.wantUnknown; !strings.HasSuffix(err.Error(), want) {
+				t.Errorf("error %q does not end with %q", err, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// workspaceMember is an entry in the members list of a workspace Cargo.toml.
+type workspaceMember struct {
+	path string
+	// comments are the comment lines that precede the entry, and any
+	// trailing comment on the same line.
+	comments []string
+	trailing string
+}
+
+// updateWorkspaceMembers rewrites the members list of the workspace
+// Cargo.toml at manifest so that it includes crates, without duplicates and
+// in sorted order. It uses a line-based approach, like updateCargoVersion, so
+// the rest of the file is left untouched. Comments inside the list move with
+// the member that follows them. Crates already matched by a glob member are
+// not added. The file is not written if the list is already up to date.
+func updateWorkspaceMembers(manifest string, crates []string) error {
+	contents, err := os.ReadFile(manifest)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(contents), "\n")
+	start, end := findWorkspaceMembers(lines)
+	if start == -1 {
+		return nil
+	}
+	members, tail := parseWorkspaceMembers(lines[start : end+1])
+
+	var merged []*workspaceMember
+	seen := make(map[string]bool)
+	for _, m := range members {
+		if seen[m.path] {
+			continue
+		}
+		seen[m.path] = true
+		merged = append(merged, m)
+	}
+	for _, crate := range crates {
+		crate = filepath.ToSlash(filepath.Clean(crate))
+		if seen[crate] || matchesGlobMember(members, crate) {
+			continue
+		}
+		seen[crate] = true
+		merged = append(merged, &workspaceMember{path: crate})
+	}
+	slices.SortStableFunc(merged, func(a, b *workspaceMember) int {
+		return strings.Compare(a.path, b.path)
+	})
+	if slices.Equal(members, merged) {
+		return nil
+	}
+
+	var list []string
+	list = append(list, "members = [")
+	for _, m := range merged {
+		for _, c := range m.comments {
+			list = append(list, "  "+c)
+		}
+		entry := `  "` + m.path + `",`
+		if m.trailing != "" {
+			entry += " " + m.trailing
+		}
+		list = append(list, entry)
+	}
+	for _, c := range tail {
+		list = append(list, "  "+c)
+	}
+	list = append(list, "]")
+
+	lines = slices.Replace(lines, start, end+1, list...)
+	return os.WriteFile(manifest, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// findWorkspaceMembers returns the indexes of the first and last lines of the
+// members list in the [workspace] table, or -1, -1 if there is none.
+func findWorkspaceMembers(lines []string) (int, int) {
+	var table string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			table = trimmed
+			continue
+		}
+		if table != "[workspace]" {
+			continue
+		}
+		key, _, ok := strings.Cut(trimmed, "=")
+		if !ok || strings.TrimSpace(key) != "members" {
+			continue
+		}
+		for j := i; j < len(lines); j++ {
+			if strings.Contains(stripComment(lines[j]), "]") {
+				return i, j
+			}
+		}
+		return -1, -1
+	}
+	return -1, -1
+}
+
+// parseWorkspaceMembers parses the members list in lines. It returns the
+// members in their original order, and any comment lines after the last
+// member.
+func parseWorkspaceMembers(lines []string) ([]*workspaceMember, []string) {
+	var (
+		members  []*workspaceMember
+		comments []string
+	)
+	for i, line := range lines {
+		if i == 0 {
+			// Drop the "members =" prefix.
+			_, line, _ = strings.Cut(line, "=")
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			comments = append(comments, trimmed)
+			continue
+		}
+		value := stripComment(trimmed)
+		trailing := strings.TrimSpace(strings.TrimPrefix(trimmed, value))
+		value = strings.Trim(strings.TrimSpace(value), "[]")
+		var added *workspaceMember
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.Trim(strings.TrimSpace(entry), `"'`)
+			if entry == "" {
+				continue
+			}
+			added = &workspaceMember{path: entry, comments: comments}
+			comments = nil
+			members = append(members, added)
+		}
+		switch {
+		case added != nil:
+			added.trailing = trailing
+		case trailing != "":
+			comments = append(comments, trailing)
+		}
+	}
+	return members, comments
+}
+
+// stripComment returns line without any trailing comment. Member paths never
+// contain "#", so there is no need to handle it inside strings.
+func stripComment(line string) string {
+	if i := strings.Index(line, "#"); i != -1 {
+		return line[:i]
+	}
+	return line
+}
+
+func matchesGlobMember(members []*workspaceMember, crate string) bool {
+	for _, m := range members {
+		if !strings.ContainsAny(m.path, "*?[") {
+			continue
+		}
+		if ok, _ := path.Match(m.path, crate); ok {
+			return true
+		}
+	}
+	return false
+}
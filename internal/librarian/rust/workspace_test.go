@@ -0,0 +1,134 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpdateWorkspaceMembers(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		initial string
+		crates  []string
+		want    string
+	}{
+		{
+			name: "sort, dedupe and add",
+			initial: `[workspace]
+members = [
+  "src/generated/b",
+  # Hand-written crates.
+  "src/auth",
+  "src/generated/b",
+  "src/generated/a", # Trailing comment.
+]
+resolver = "2"
+
+[workspace.dependencies]
+members = "not the workspace members"
+`,
+			crates: []string{"src/generated/c", "src/generated/a", "src/generated/0"},
+			want: `[workspace]
+members = [
+  # Hand-written crates.
+  "src/auth",
+  "src/generated/0",
+  "src/generated/a", # Trailing comment.
+  "src/generated/b",
+  "src/generated/c",
+]
+resolver = "2"
+
+[workspace.dependencies]
+members = "not the workspace members"
+`,
+		},
+		{
+			name: "single line",
+			initial: `[workspace]
+members = ["b", "a"]
+`,
+			crates: []string{"c"},
+			want: `[workspace]
+members = [
+  "a",
+  "b",
+  "c",
+]
+`,
+		},
+		{
+			name: "matched by glob",
+			initial: `[workspace]
+members = ["src/generated/*"]
+`,
+			crates: []string{"src/generated/a"},
+			want: `[workspace]
+members = ["src/generated/*"]
+`,
+		},
+		{
+			name: "already up to date",
+			initial: `[workspace]
+members = ["a", "b"]
+`,
+			crates: []string{"./a"},
+			want: `[workspace]
+members = ["a", "b"]
+`,
+		},
+		{
+			name: "no members",
+			initial: `[package]
+name = "crate"
+`,
+			crates: []string{"a"},
+			want: `[package]
+name = "crate"
+`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			manifest := filepath.Join(t.TempDir(), "Cargo.toml")
+			if err := os.WriteFile(manifest, []byte(test.initial), 0644); err != nil {
+				t.Fatal(err)
+			}
+			// Running twice must give the same result.
+			for range 2 {
+				if err := updateWorkspaceMembers(manifest, test.crates); err != nil {
+					t.Fatal(err)
+				}
+				got, err := os.ReadFile(manifest)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(test.want, string(got)); diff != "" {
+					t.Errorf("mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateWorkspaceMembers_Error(t *testing.T) {
+	if err := updateWorkspaceMembers(filepath.Join(t.TempDir(), "missing.toml"), nil); err == nil {
+		t.Error("expected an error for a missing manifest")
+	}
+}
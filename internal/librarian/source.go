@@ -17,11 +17,22 @@ package librarian
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/googleapis/librarian/internal/config"
 	"github.com/googleapis/librarian/internal/fetch"
+	"golang.org/x/sync/errgroup"
 )
 
+// includeSources holds the directories of the optional source repositories
+// that generators can use as additional include roots. A directory is empty
+// when its source is not configured.
+type includeSources struct {
+	Conformance string
+	ProtobufSrc string
+	Showcase    string
+}
+
 // fetchSource fetches a repository source.
 func fetchSource(ctx context.Context, source *config.Source, repo string) (string, error) {
 	if source == nil {
@@ -37,3 +48,41 @@ func fetchSource(ctx context.Context, source *config.Source, repo string) (strin
 	}
 	return dir, nil
 }
+
+// fetchIncludeSources fetches the showcase, conformance and protobuf sources,
+// if configured, in parallel. The protobuf directory includes the configured
+// subpath.
+func fetchIncludeSources(ctx context.Context, cfgSources *config.Sources) (*includeSources, error) {
+	sources := &includeSources{}
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		dir, err := fetchSource(ctx, cfgSources.Conformance, protobufRepo)
+		if err != nil {
+			return err
+		}
+		sources.Conformance = dir
+		return nil
+	})
+	g.Go(func() error {
+		dir, err := fetchSource(ctx, cfgSources.Showcase, showcaseRepo)
+		if err != nil {
+			return err
+		}
+		sources.Showcase = dir
+		return nil
+	})
+	if cfgSources.ProtobufSrc != nil {
+		g.Go(func() error {
+			dir, err := fetchSource(ctx, cfgSources.ProtobufSrc, protobufRepo)
+			if err != nil {
+				return err
+			}
+			sources.ProtobufSrc = filepath.Join(dir, cfgSources.ProtobufSrc.Subpath)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
@@ -15,6 +15,13 @@
 package librarian
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -52,3 +59,137 @@ func TestFetchSource(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchIncludeSources(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("LIBRARIAN_CACHE", cacheDir)
+	// Populate the cache so that fetched sources resolve without a download.
+	cached := func(repo, commit string) string {
+		dir := filepath.Join(cacheDir, repo+"@"+commit)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("cached"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	conformanceDir := cached(protobufRepo, "conformance-commit")
+	protobufDir := cached(protobufRepo, "protobuf-commit")
+	showcaseDir := cached(showcaseRepo, "showcase-commit")
+
+	for _, test := range []struct {
+		name    string
+		sources *config.Sources
+		want    *includeSources
+	}{
+		{
+			name:    "not configured",
+			sources: &config.Sources{},
+			want:    &includeSources{},
+		},
+		{
+			name: "local directories",
+			sources: &config.Sources{
+				Conformance: &config.Source{Dir: "local/conformance"},
+				ProtobufSrc: &config.Source{Dir: "local/protobuf", Subpath: "src"},
+				Showcase:    &config.Source{Dir: "local/showcase"},
+			},
+			want: &includeSources{
+				Conformance: "local/conformance",
+				ProtobufSrc: filepath.Join("local/protobuf", "src"),
+				Showcase:    "local/showcase",
+			},
+		},
+		{
+			name: "fetched",
+			sources: &config.Sources{
+				Conformance: &config.Source{Commit: "conformance-commit"},
+				ProtobufSrc: &config.Source{Commit: "protobuf-commit", Subpath: "src"},
+				Showcase:    &config.Source{Commit: "showcase-commit"},
+			},
+			want: &includeSources{
+				Conformance: conformanceDir,
+				ProtobufSrc: filepath.Join(protobufDir, "src"),
+				Showcase:    showcaseDir,
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := fetchIncludeSources(t.Context(), test.sources)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFetchRustSources_CachedDiscovery(t *testing.T) {
+	const commit = "0123456789abcdef"
+	cacheDir := t.TempDir()
+	t.Setenv("LIBRARIAN_CACHE", cacheDir)
+
+	// Place the discovery tarball in the download cache, as if an earlier
+	// run had downloaded it.
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	content := []byte(`{"kind": "discovery#restDescription"}`)
+	if err := tw.WriteHeader(&tar.Header{
+		Name: "discovery-artifact-manager-" + commit + "/discoveries/compute.v1.json",
+		Mode: 0644,
+		Size: int64(len(content)),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tarball := filepath.Join(cacheDir, "download", filepath.Dir(discoveryRepo), filepath.Base(discoveryRepo)+"@"+commit+".tar.gz")
+	if err := os.MkdirAll(filepath.Dir(tarball), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tarball, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sources := &config.Sources{
+		Discovery: &config.Source{
+			Commit: commit,
+			SHA256: fmt.Sprintf("%x", sha256.Sum256(buf.Bytes())),
+		},
+	}
+	first, err := fetchRustSources(t.Context(), sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(cacheDir, discoveryRepo+"@"+commit)
+	if diff := cmp.Diff(want, first.Discovery); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without the tarball, the second fetch can only succeed by reusing the
+	// extracted directory.
+	if err := os.Remove(tarball); err != nil {
+		t.Fatal(err)
+	}
+	second, err := fetchRustSources(t.Context(), sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(first.Discovery, second.Discovery); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := os.Stat(filepath.Join(second.Discovery, "discoveries", "compute.v1.json")); err != nil {
+		t.Error(err)
+	}
+}
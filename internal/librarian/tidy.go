@@ -22,7 +22,6 @@ import (
 	"strings"
 
 	"github.com/googleapis/librarian/internal/config"
-	"github.com/googleapis/librarian/internal/yaml"
 	"github.com/urfave/cli/v3"
 )
 
@@ -36,13 +35,26 @@ func tidyCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "tidy",
 		Usage:     "format and validate librarian.yaml",
-		UsageText: "librarian tidy [path]",
+		UsageText: "librarian tidy [path] [--fix]",
+		Description: `tidy formats and validates librarian.yaml. It also reports directories
+under the default output root that belong to no configured library, such as
+the output of a library that was removed from librarian.yaml. Paths listed in
+a library's keep list and .git directories are never reported.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "fix",
+				Usage: "remove output directories that belong to no library",
+			},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			cfg, err := loadConfig(ctx)
 			if err != nil {
 				return err
 			}
-			return RunTidyOnConfig(ctx, cfg)
+			if err := RunTidyOnConfig(ctx, cfg); err != nil {
+				return err
+			}
+			return tidyOutputs(cfg, cmd.Bool("fix"))
 		},
 	}
 }
@@ -62,7 +74,7 @@ func RunTidyOnConfig(ctx context.Context, cfg *config.Config) error {
 			return err
 		}
 	}
-	return yaml.Write(librarianConfigPath, formatConfig(cfg))
+	return writeConfig(configPath(ctx), formatConfig(cfg))
 }
 
 func tidyLibrary(cfg *config.Config, lib *config.Library) error {
@@ -0,0 +1,143 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+)
+
+// tidyOutputs reports the directories under the default output root that
+// belong to no configured library, and removes them if fix is set.
+func tidyOutputs(cfg *config.Config, fix bool) error {
+	orphans, err := findOrphanedOutputs(cfg)
+	if err != nil {
+		return err
+	}
+	for _, dir := range orphans {
+		if !fix {
+			slog.Warn("output directory belongs to no library; run with --fix to remove it", "dir", dir)
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		slog.Info("removed output directory that belongs to no library", "dir", dir)
+	}
+	return nil
+}
+
+// findOrphanedOutputs walks the default output root and returns, in lexical
+// order, the directories that are neither a library directory, inside one,
+// nor on the path to one. Paths kept by a library's keep list are never
+// reported, and .git directories are skipped. A library whose directory is
+// the root itself owns nothing by itself, since the root is shared. For Go,
+// where every library lives in a directory under the shared root, only
+// directories holding a go.mod are candidates, so that shared directories
+// such as internal are never reported. It returns nil if no default output
+// root is configured or the root does not exist.
+func findOrphanedOutputs(cfg *config.Config) ([]string, error) {
+	if cfg.Default == nil || cfg.Default.Output == "" {
+		return nil, nil
+	}
+	root := filepath.Clean(cfg.Default.Output)
+	var owned []string
+	for _, lib := range cfg.Libraries {
+		dir := libraryDir(cfg.Language, lib, cfg.Default)
+		if dir == "" {
+			continue
+		}
+		dir = filepath.Clean(dir)
+		if dir != root {
+			owned = append(owned, dir)
+		}
+		output := filepath.Clean(libraryOutput(cfg.Language, lib, cfg.Default))
+		for _, keep := range lib.Keep {
+			owned = append(owned, filepath.Join(output, keep))
+		}
+	}
+
+	var orphans []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		switch ownership(path, owned) {
+		case ownedDir:
+			return filepath.SkipDir
+		case parentDir:
+			return nil
+		}
+		if cfg.Language == languageGo && !isGoModule(path) {
+			return filepath.SkipDir
+		}
+		orphans = append(orphans, path)
+		return filepath.SkipDir
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return orphans, err
+}
+
+// isGoModule reports whether dir holds a go.mod file.
+func isGoModule(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil
+}
+
+type dirOwnership int
+
+const (
+	unownedDir dirOwnership = iota
+	// ownedDir is a library directory, a kept path, or inside one of them.
+	ownedDir
+	// parentDir contains a library directory or kept path.
+	parentDir
+)
+
+func ownership(dir string, owned []string) dirOwnership {
+	result := unownedDir
+	for _, o := range owned {
+		if matched, _ := filepath.Match(o, dir); matched || isWithin(dir, o) {
+			return ownedDir
+		}
+		if isWithin(o, dir) {
+			result = parentDir
+		}
+	}
+	return result
+}
+
+// isWithin reports whether path is dir or inside it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
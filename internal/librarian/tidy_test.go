@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -203,6 +204,112 @@ libraries:
 	}
 }
 
+func TestTidyCommand_OrphanedOutputs(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		args        []string
+		wantRemoved bool
+	}{
+		{
+			name: "report",
+			args: []string{"librarian", "tidy"},
+		},
+		{
+			name:        "fix",
+			args:        []string{"librarian", "tidy", "--fix"},
+			wantRemoved: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			t.Chdir(tempDir)
+			configContent := fmt.Sprintf(`language: fake
+version: %s
+sources:
+  googleapis:
+    commit: 94ccedca05acb0bb60780789e93371c9e4100ddc
+    sha256: fff40946e897d96bbdccd566cb993048a87029b7e08eacee3fe99eac792721ba
+default:
+  output: src
+libraries:
+  - name: google-cloud-storage
+    output: src/storage
+  - name: gax-internal
+    output: src/gax/internal
+    keep:
+      - ../testdata
+`, sample.LibrarianVersion)
+			if err := os.WriteFile(librarianConfigPath, []byte(configContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+			for _, dir := range []string{
+				"src/storage/v1",
+				"src/gax/internal",
+				"src/gax/testdata",
+				"src/.git",
+				"src/old-library/v1",
+			} {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			buf := captureLogs(t)
+			if err := Run(t.Context(), test.args...); err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(buf.String(), "dir=src/old-library") {
+				t.Errorf("orphaned output not reported:\n%s", buf.String())
+			}
+			_, err := os.Stat(filepath.Join("src", "old-library"))
+			if gotRemoved := errors.Is(err, os.ErrNotExist); gotRemoved != test.wantRemoved {
+				t.Errorf("src/old-library removed = %t, want %t", gotRemoved, test.wantRemoved)
+			}
+			for _, dir := range []string{"src/storage/v1", "src/gax/internal", "src/gax/testdata", "src/.git"} {
+				if _, err := os.Stat(dir); err != nil {
+					t.Errorf("%s should not be removed: %v", dir, err)
+				}
+			}
+		})
+	}
+}
+
+func TestFindOrphanedOutputs_Go(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, dir := range []string{
+		"secretmanager/apiv1",
+		"pubsub/apiv1",
+		"internal/generated/snippets",
+		".git/objects",
+		"oldlibrary/apiv1",
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dir := range []string{"secretmanager", "pubsub", "oldlibrary"} {
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{
+		Language: languageGo,
+		Default:  &config.Default{Output: "."},
+		Libraries: []*config.Library{
+			{Name: "secretmanager"},
+			{Name: "pubsub", Output: "."},
+		},
+	}
+	got, err := findOrphanedOutputs(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"oldlibrary"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestTidy_DerivableFields(t *testing.T) {
 	googleapisSource := &config.Sources{
 		Googleapis: &config.Source{
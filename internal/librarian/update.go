@@ -22,7 +22,6 @@ import (
 
 	"github.com/googleapis/librarian/internal/config"
 	"github.com/googleapis/librarian/internal/fetch"
-	"github.com/googleapis/librarian/internal/yaml"
 	"github.com/urfave/cli/v3"
 )
 
@@ -40,6 +39,7 @@ var (
 	errBothSourceAndAllFlag   = errors.New("cannot specify a source when --all is set")
 	errMissingSourceOrAllFlag = errors.New("a source must be specified, or use the --all flag")
 	errUnknownSource          = errors.New("unknown source")
+	errSinceRequiresDir       = errors.New("--since requires sources.googleapis.dir to be set")
 )
 
 // updateCommand returns the `update` subcommand.
@@ -52,22 +52,43 @@ func updateCommand() *cli.Command {
   - discovery
   - googleapis
   - protobuf
-  - showcase`,
-		UsageText: "librarian update [--all | source]",
+  - showcase
+
+With --generate, libraries whose APIs changed in googleapis since they were
+last generated are regenerated. If sources.googleapis.dir is set, changes are
+found in the git history of that checkout. Otherwise, the API directories of
+the pinned googleapis commit are compared with those of the commit each
+library was last generated from. That commit is recorded for each library in
+librarian-state.yaml.
+
+With --since, changes are instead found since the given googleapis commit for
+every library, which is useful to backfill from a known-good point. It implies
+--generate and requires sources.googleapis.dir.`,
+		UsageText: "librarian update [--all | source] [--generate] [--since commit]",
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "all",
 				Usage: "update discovery and googleapis sources",
 			},
+			&cli.BoolFlag{
+				Name:  "generate",
+				Usage: "regenerate only the libraries whose APIs changed since they were last generated",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "with --generate, find API changes since this googleapis commit instead of since each library was last generated",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			all := cmd.Bool("all")
+			since := cmd.String("since")
+			generate := cmd.Bool("generate") || since != ""
 			source := cmd.Args().First()
 
 			if all && source != "" {
 				return errBothSourceAndAllFlag
 			}
-			if !all && source == "" {
+			if !all && source == "" && !generate {
 				return errMissingSourceOrAllFlag
 			}
 			if source != "" {
@@ -82,12 +103,20 @@ func updateCommand() *cli.Command {
 			if err != nil {
 				return err
 			}
-			return runUpdate(cfg, all, source)
+			if all || source != "" {
+				if err := runUpdate(ctx, cfg, all, source); err != nil {
+					return err
+				}
+			}
+			if generate {
+				return runGenerateChanged(ctx, cfg, since)
+			}
+			return nil
 		},
 	}
 }
 
-func runUpdate(cfg *config.Config, all bool, sourceName string) error {
+func runUpdate(ctx context.Context, cfg *config.Config, all bool, sourceName string) error {
 	if cfg.Sources == nil {
 		return errEmptySources
 	}
@@ -115,14 +144,14 @@ func runUpdate(cfg *config.Config, all bool, sourceName string) error {
 	for _, name := range sourceNamesToProcess {
 		source := sourcesMap[name]
 		repo := sourceRepos[name]
-		if err := updateSource(endpoints, repo, source, cfg); err != nil {
+		if err := updateSource(endpoints, repo, source, cfg, configPath(ctx)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func updateSource(endpoints *fetch.Endpoints, repo fetch.Repo, source *config.Source, cfg *config.Config) error {
+func updateSource(endpoints *fetch.Endpoints, repo fetch.Repo, source *config.Source, cfg *config.Config, cfgPath string) error {
 	if source == nil {
 		return nil
 	}
@@ -144,7 +173,7 @@ func updateSource(endpoints *fetch.Endpoints, repo fetch.Repo, source *config.So
 	if oldCommit != commit || oldSHA256 != sha256 {
 		source.Commit = commit
 		source.SHA256 = sha256
-		if err := yaml.Write(librarianConfigPath, cfg); err != nil {
+		if err := writeConfig(cfgPath, cfg); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,240 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/googleapis/librarian/internal/command"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/fetch"
+	"github.com/googleapis/librarian/internal/git"
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+// generationStatePath is the file, alongside librarian.yaml, that records the
+// googleapis commit each library was last generated from. It is kept out of
+// librarian.yaml because it changes on every run of update --generate, for
+// every library, while librarian.yaml only changes when the configuration
+// does.
+const generationStatePath = "librarian-state.yaml"
+
+// generationState is the content of generationStatePath.
+type generationState struct {
+	// Libraries maps a library name to the googleapis source it was last
+	// generated from.
+	Libraries map[string]*generatedSource `yaml:"libraries,omitempty"`
+}
+
+// generatedSource identifies a googleapis commit a library was generated
+// from.
+type generatedSource struct {
+	// Commit is the googleapis commit.
+	Commit string `yaml:"commit"`
+
+	// SHA256 is the checksum of the googleapis tarball at Commit. It is empty
+	// when the library was generated from a local checkout.
+	SHA256 string `yaml:"sha256,omitempty"`
+}
+
+func readGenerationState(path string) (*generationState, error) {
+	state, err := yaml.Read[generationState](path)
+	if errors.Is(err, fs.ErrNotExist) {
+		state, err = &generationState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if state.Libraries == nil {
+		state.Libraries = make(map[string]*generatedSource)
+	}
+	return state, nil
+}
+
+// runGenerateChanged regenerates the libraries whose APIs have changed in
+// googleapis since they were last generated, and records the current
+// googleapis commit for every library it considered. With a local googleapis
+// checkout, changes are found in its git history. Otherwise, the API
+// directories of the pinned googleapis tarball are compared with those of the
+// tarball each library was last generated from.
+//
+// If since is set, changes are found in the local checkout since that commit
+// for every library, regardless of when it was last generated.
+func runGenerateChanged(ctx context.Context, cfg *config.Config, since string) error {
+	if cfg.Sources == nil || cfg.Sources.Googleapis == nil {
+		return errEmptySources
+	}
+	if since != "" && cfg.Sources.Googleapis.Dir == "" {
+		return errSinceRequiresDir
+	}
+	gitExe := "git"
+	if cfg.Release != nil {
+		gitExe = command.GetExecutablePath(cfg.Release.Preinstalled, "git")
+	}
+	source := cfg.Sources.Googleapis
+	googleapisDir, err := fetchSource(ctx, source, googleapisRepo)
+	if err != nil {
+		return err
+	}
+	current := &generatedSource{Commit: source.Commit, SHA256: source.SHA256}
+	if source.Dir != "" {
+		head, err := git.HeadCommit(ctx, gitExe, googleapisDir)
+		if err != nil {
+			return err
+		}
+		current = &generatedSource{Commit: head}
+	}
+	statePath := filepath.Join(filepath.Dir(configPath(ctx)), generationStatePath)
+	state, err := readGenerationState(statePath)
+	if err != nil {
+		return err
+	}
+
+	var considered, changed []string
+	for _, lib := range cfg.Libraries {
+		if lib.SkipGenerate {
+			continue
+		}
+		considered = append(considered, lib.Name)
+		paths := libraryAPIPaths(cfg.Language, lib)
+		last := state.Libraries[lib.Name]
+		if since != "" {
+			last = &generatedSource{Commit: since}
+		}
+		var ok bool
+		if source.Dir != "" {
+			ok, err = apisChangedSince(ctx, gitExe, googleapisDir, last, current, paths)
+		} else {
+			ok, err = apisChangedBetween(ctx, googleapisDir, last, current, paths)
+		}
+		if err != nil {
+			return err
+		}
+		if ok {
+			changed = append(changed, lib.Name)
+		}
+	}
+	if len(changed) == 0 {
+		slog.Info("no API changes since the last generation", "googleapis", current.Commit)
+	} else if err := runGenerate(ctx, cfg, false, changed, generateOptions{}); err != nil {
+		return err
+	}
+	for _, name := range considered {
+		state.Libraries[name] = current
+	}
+	return yaml.Write(statePath, state)
+}
+
+// apisChangedSince reports whether any of paths changed in the googleapis
+// repository in dir between the commits of last and head. A nil last means
+// the library has never been generated, so it is always considered changed.
+func apisChangedSince(ctx context.Context, gitExe, dir string, last, head *generatedSource, paths []string) (bool, error) {
+	if last == nil {
+		return true, nil
+	}
+	if last.Commit == head.Commit || len(paths) == 0 {
+		return false, nil
+	}
+	commits, err := git.FindCommitsForPathsSince(ctx, gitExe, dir, last.Commit, paths)
+	if err != nil {
+		return false, err
+	}
+	return len(commits) > 0, nil
+}
+
+// apisChangedBetween reports whether any of paths differ between the
+// googleapis tarball of last and the googleapis tree in dir, which was
+// fetched for current. A library that has never been generated, or was last
+// generated from a local checkout and so has no tarball checksum, is always
+// considered changed.
+func apisChangedBetween(ctx context.Context, dir string, last, current *generatedSource, paths []string) (bool, error) {
+	if last == nil || last.SHA256 == "" {
+		return true, nil
+	}
+	if last.Commit == current.Commit || len(paths) == 0 {
+		return false, nil
+	}
+	lastDir, err := fetch.RepoDir(ctx, googleapisRepo, last.Commit, last.SHA256)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch %s: %w", googleapisRepo, err)
+	}
+	for _, path := range paths {
+		before, err := treeDigest(filepath.Join(lastDir, path))
+		if err != nil {
+			return false, err
+		}
+		after, err := treeDigest(filepath.Join(dir, path))
+		if err != nil {
+			return false, err
+		}
+		if !maps.Equal(before, after) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// treeDigest returns the SHA-256 of every file under dir, keyed by its path
+// relative to dir. A missing dir has no files.
+func treeDigest(dir string) (map[string]string, error) {
+	digests := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		digests[filepath.ToSlash(rel)] = hex.EncodeToString(sum[:])
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return digests, nil
+	}
+	return digests, err
+}
+
+// libraryAPIPaths returns the API paths of lib, deriving one from the library
+// name if none is configured. Veneers without APIs have no API paths.
+func libraryAPIPaths(language string, lib *config.Library) []string {
+	var paths []string
+	for _, api := range lib.APIs {
+		if api.Path != "" {
+			paths = append(paths, api.Path)
+		}
+	}
+	if len(paths) == 0 && !lib.Veneer {
+		paths = append(paths, deriveAPIPath(language, lib.Name))
+	}
+	return paths
+}
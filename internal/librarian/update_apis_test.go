@@ -0,0 +1,328 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/command"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/git"
+	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/testhelper"
+	"github.com/googleapis/librarian/internal/yaml"
+)
+
+func TestUpdateCommand_Generate(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	googleapisDir := createGoogleapisServiceConfigs(t, t.TempDir(), map[string]string{
+		"google/cloud/speech/v1":       "speech_v1.yaml",
+		"google/cloud/texttospeech/v1": "texttospeech_v1.yaml",
+	})
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@test-only.com"},
+		{"config", "user.name", "Test Account"},
+	} {
+		runGit(t, googleapisDir, args...)
+	}
+	commitAll(t, googleapisDir, "initial commit")
+
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+		{
+			Name:   "library-two",
+			Output: "output2",
+			APIs:   []*config.API{{Path: "google/cloud/texttospeech/v1"}},
+		},
+	}
+	if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		change string
+		want   []string
+	}{
+		{
+			name: "never generated",
+			want: []string{"output1", "output2"},
+		},
+		{
+			name: "unchanged",
+		},
+		{
+			name:   "one api changed",
+			change: "google/cloud/speech/v1/speech.proto",
+			want:   []string{"output1"},
+		},
+		{
+			name:   "unrelated change",
+			change: "google/cloud/other/v1/other.proto",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.change != "" {
+				path := filepath.Join(googleapisDir, test.change)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(test.name), 0644); err != nil {
+					t.Fatal(err)
+				}
+				commitAll(t, googleapisDir, test.name)
+			}
+			for _, output := range []string{"output1", "output2"} {
+				if err := os.RemoveAll(filepath.Join(tempDir, output, "README.md")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := Run(t.Context(), "librarian", "update", "--generate"); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, output := range []string{"output1", "output2"} {
+				if _, err := os.Stat(filepath.Join(tempDir, output, "README.md")); err == nil {
+					got = append(got, output)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+
+			head, err := git.HeadCommit(t.Context(), "git", googleapisDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			state, err := readGenerationState(generationStatePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantState := map[string]*generatedSource{
+				"library-one": {Commit: head},
+				"library-two": {Commit: head},
+			}
+			if diff := cmp.Diff(wantState, state.Libraries); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateCommand_GenerateSince(t *testing.T) {
+	testhelper.RequireCommand(t, "git")
+	googleapisDir := createGoogleapisServiceConfigs(t, t.TempDir(), map[string]string{
+		"google/cloud/speech/v1":       "speech_v1.yaml",
+		"google/cloud/texttospeech/v1": "texttospeech_v1.yaml",
+	})
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@test-only.com"},
+		{"config", "user.name", "Test Account"},
+	} {
+		runGit(t, googleapisDir, args...)
+	}
+	commitAll(t, googleapisDir, "initial commit")
+	base, err := git.HeadCommit(t.Context(), "git", googleapisDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(googleapisDir, "google/cloud/speech/v1/speech.proto"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commitAll(t, googleapisDir, "change speech")
+	head, err := git.HeadCommit(t.Context(), "git", googleapisDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Dir: googleapisDir}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+		{
+			Name:   "library-two",
+			Output: "output2",
+			APIs:   []*config.API{{Path: "google/cloud/texttospeech/v1"}},
+		},
+	}
+	if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), cfg); err != nil {
+		t.Fatal(err)
+	}
+	// Both libraries were last generated from head, so without --since
+	// nothing would be regenerated.
+	if err := yaml.Write(generationStatePath, &generationState{Libraries: map[string]*generatedSource{
+		"library-one": {Commit: head},
+		"library-two": {Commit: head},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(t.Context(), "librarian", "update", "--since", base); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, output := range []string{"output1", "output2"} {
+		if _, err := os.Stat(filepath.Join(tempDir, output, "README.md")); err == nil {
+			got = append(got, output)
+		}
+	}
+	if diff := cmp.Diff([]string{"output1"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpdateCommand_GenerateFromTarball(t *testing.T) {
+	const (
+		lastCommit = "1111111111111111111111111111111111111111"
+		headCommit = "2222222222222222222222222222222222222222"
+	)
+	cacheDir := t.TempDir()
+	t.Setenv("LIBRARIAN_CACHE", cacheDir)
+	configs := map[string]string{
+		"google/cloud/speech/v1":       "speech_v1.yaml",
+		"google/cloud/texttospeech/v1": "texttospeech_v1.yaml",
+	}
+	for _, commit := range []string{lastCommit, headCommit} {
+		dir := filepath.Join(cacheDir, googleapisRepo+"@"+commit)
+		for apiPath, filename := range configs {
+			if err := os.MkdirAll(filepath.Join(dir, apiPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, apiPath, filename), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	changed := filepath.Join(cacheDir, googleapisRepo+"@"+headCommit, "google/cloud/speech/v1/speech.proto")
+	if err := os.WriteFile(changed, []byte("syntax = \"proto3\";\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Sources.Googleapis = &config.Source{Commit: headCommit, SHA256: "head-sha256"}
+	cfg.Libraries = []*config.Library{
+		{
+			Name:   "library-one",
+			Output: "output1",
+			APIs:   []*config.API{{Path: "google/cloud/speech/v1"}},
+		},
+		{
+			Name:   "library-two",
+			Output: "output2",
+			APIs:   []*config.API{{Path: "google/cloud/texttospeech/v1"}},
+		},
+	}
+	if err := yaml.Write(librarianConfigPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+	last := &generatedSource{Commit: lastCommit, SHA256: "last-sha256"}
+	state := &generationState{Libraries: map[string]*generatedSource{"library-one": last, "library-two": last}}
+	if err := yaml.Write(generationStatePath, state); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(t.Context(), "librarian", "update", "--generate"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, output := range []string{"output1", "output2"} {
+		if _, err := os.Stat(filepath.Join(output, "README.md")); err == nil {
+			got = append(got, output)
+		}
+	}
+	if diff := cmp.Diff([]string{"output1"}, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	gotState, err := readGenerationState(generationStatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := &generatedSource{Commit: headCommit, SHA256: "head-sha256"}
+	wantState := map[string]*generatedSource{"library-one": head, "library-two": head}
+	if diff := cmp.Diff(wantState, gotState.Libraries); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLibraryAPIPaths(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		library *config.Library
+		want    []string
+	}{
+		{
+			name: "configured apis",
+			library: &config.Library{
+				Name: "google-cloud-speech",
+				APIs: []*config.API{{Path: "google/cloud/speech/v1"}, {Path: "google/cloud/speech/v2"}},
+			},
+			want: []string{"google/cloud/speech/v1", "google/cloud/speech/v2"},
+		},
+		{
+			name:    "derived api",
+			library: &config.Library{Name: "google-cloud-speech-v1"},
+			want:    []string{"google/cloud/speech/v1"},
+		},
+		{
+			name:    "veneer without apis",
+			library: &config.Library{Name: "google-cloud-speech", Veneer: true},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := libraryAPIPaths(languageFake, test.library)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if err := command.Run(t.Context(), "git", append([]string{"-C", dir}, args...)...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func commitAll(t *testing.T, dir, msg string) {
+	t.Helper()
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", msg)
+}
@@ -284,6 +284,26 @@ func TestUpdateCommand_Errors(t *testing.T) {
 			}(),
 			wantErr: errEmptySources,
 		},
+		{
+			name: "generate without googleapis source",
+			args: []string{"librarian", "update", "--generate"},
+			conf: func() *config.Config {
+				cfg := sample.Config()
+				cfg.Sources.Googleapis = nil
+				return cfg
+			}(),
+			wantErr: errEmptySources,
+		},
+		{
+			name: "since without googleapis dir",
+			args: []string{"librarian", "update", "--since", "abc123"},
+			conf: func() *config.Config {
+				cfg := sample.Config()
+				cfg.Sources.Googleapis = &config.Source{Commit: "abc123", SHA256: "def456"}
+				return cfg
+			}(),
+			wantErr: errSinceRequiresDir,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			setupTestConfig(t, test.conf)
@@ -15,14 +15,17 @@
 package librarian
 
 import (
+	"log/slog"
 	"testing"
 
 	"github.com/googleapis/librarian/internal/command"
 )
 
 func TestVerboseFlag(t *testing.T) {
+	defaultLogger := slog.Default()
 	t.Cleanup(func() {
 		command.Verbose = false
+		slog.SetDefault(defaultLogger)
 	})
 
 	for _, test := range []struct {
@@ -0,0 +1,193 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+var (
+	errMissingGolden  = errors.New("must specify a golden archive with --golden")
+	errGoldenMismatch = errors.New("generated library does not match golden archive")
+)
+
+func verifyGoldenCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "verify-golden",
+		Usage:     "compare a freshly generated library with a golden archive",
+		UsageText: "librarian verify-golden <library> --golden <file>",
+		Description: `verify-golden generates a library and compares its output directory with a
+golden archive, such as one written by "librarian package". Every file that is
+missing, unexpected or has different content is reported, and the command
+fails if there is any difference.
+
+Examples:
+  librarian package <library> -o golden.tar.gz
+  librarian verify-golden <library> --golden golden.tar.gz`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "golden",
+				Usage: "the golden `file`, a gzip-compressed tar archive",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			libraryName := cmd.Args().First()
+			if libraryName == "" {
+				return errMissingLibrary
+			}
+			golden := cmd.String("golden")
+			if golden == "" {
+				return errMissingGolden
+			}
+			cfg, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+			return runVerifyGolden(ctx, cfg, libraryName, golden)
+		},
+	}
+}
+
+func runVerifyGolden(ctx context.Context, cfg *config.Config, libraryName, golden string) error {
+	want, err := readArchive(golden)
+	if err != nil {
+		return err
+	}
+	if err := runGenerate(ctx, cfg, false, []string{libraryName}, generateOptions{}); err != nil {
+		return err
+	}
+	lib, err := findLibrary(cfg, libraryName)
+	if err != nil {
+		return err
+	}
+	library, err := applyDefaults(cfg.Language, lib, cfg.Default)
+	if err != nil {
+		return err
+	}
+	got, err := readLibraryOutput(cfg, library)
+	if err != nil {
+		return err
+	}
+	if diffs := goldenDiff(want, got); len(diffs) > 0 {
+		return fmt.Errorf("%w %s:\n%s", errGoldenMismatch, golden, strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+// goldenDiff returns one line per file that differs between the golden
+// contents want and the generated contents got, sorted by file name.
+func goldenDiff(want, got map[string]string) []string {
+	names := make(map[string]bool)
+	for name := range want {
+		names[name] = true
+	}
+	for name := range got {
+		names[name] = true
+	}
+	var diffs []string
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		w, inWant := want[name]
+		g, inGot := got[name]
+		switch {
+		case !inGot:
+			diffs = append(diffs, "  missing:    "+name)
+		case !inWant:
+			diffs = append(diffs, "  unexpected: "+name)
+		case w != g:
+			diffs = append(diffs, "  changed:    "+name)
+		}
+	}
+	return diffs
+}
+
+// readArchive returns the contents of the regular files and symbolic links
+// in the gzip-compressed tar archive at path, keyed by entry name. Symbolic
+// links map to their target.
+func readArchive(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", path, err)
+			}
+			files[hdr.Name] = string(data)
+		case tar.TypeSymlink:
+			files[hdr.Name] = hdr.Linkname
+		}
+	}
+}
+
+// readLibraryOutput returns the contents of the files that packaging lib
+// would archive, in the same form as readArchive.
+func readLibraryOutput(cfg *config.Config, lib *config.Library) (map[string]string, error) {
+	dir, names, err := packageFiles(cfg, lib)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string)
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		info, err := os.Lstat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return nil, err
+			}
+			files[name] = target
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = string(data)
+	}
+	return files, nil
+}
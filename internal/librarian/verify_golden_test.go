@@ -0,0 +1,164 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVerifyGoldenCommand(t *testing.T) {
+	const (
+		libName   = "library-one"
+		libOutput = "output1"
+	)
+	for _, test := range []struct {
+		name string
+		// beforePackage and afterPackage change the generated output
+		// before and after the golden archive is written, so that the next
+		// generation no longer matches it.
+		beforePackage func(t *testing.T)
+		afterPackage  func(t *testing.T)
+		wantErr       error
+		wantDiff      []string
+	}{
+		{
+			name: "matching generation",
+		},
+		{
+			name: "changed file",
+			beforePackage: func(t *testing.T) {
+				if err := os.WriteFile(filepath.Join(libOutput, "README.md"), []byte("stale\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr:  errGoldenMismatch,
+			wantDiff: []string{"changed:    README.md"},
+		},
+		{
+			name: "file no longer generated",
+			beforePackage: func(t *testing.T) {
+				if err := os.WriteFile(filepath.Join(libOutput, "REMOVED.md"), []byte("gone\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			afterPackage: func(t *testing.T) {
+				if err := os.Remove(filepath.Join(libOutput, "REMOVED.md")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr:  errGoldenMismatch,
+			wantDiff: []string{"missing:    REMOVED.md"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			t.Chdir(tempDir)
+			googleapisDir := createGoogleapisServiceConfigs(t, tempDir, map[string]string{
+				"google/cloud/speech/v1": "speech_v1.yaml",
+			})
+			configContent := fmt.Sprintf(`language: fake
+version: v0.1.0
+sources:
+  googleapis:
+    dir: %s
+libraries:
+  - name: %s
+    output: %s
+    apis:
+      - path: google/cloud/speech/v1
+`, googleapisDir, libName, libOutput)
+			if err := os.WriteFile(filepath.Join(tempDir, librarianConfigPath), []byte(configContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := Run(t.Context(), "librarian", "generate", libName); err != nil {
+				t.Fatal(err)
+			}
+			if test.beforePackage != nil {
+				test.beforePackage(t)
+			}
+			if err := Run(t.Context(), "librarian", "package", libName, "-o", "golden.tar.gz"); err != nil {
+				t.Fatal(err)
+			}
+			if test.afterPackage != nil {
+				test.afterPackage(t)
+			}
+
+			err := Run(t.Context(), "librarian", "verify-golden", libName, "--golden", "golden.tar.gz")
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("want error %v, got %v", test.wantErr, err)
+			}
+			for _, want := range test.wantDiff {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGoldenDiff(t *testing.T) {
+	want := map[string]string{
+		"README.md": "a",
+		"VERSION":   "1.0.0",
+		"old.txt":   "x",
+	}
+	got := map[string]string{
+		"README.md": "b",
+		"VERSION":   "1.0.0",
+		"new.txt":   "y",
+	}
+	wantDiff := []string{
+		"  changed:    README.md",
+		"  unexpected: new.txt",
+		"  missing:    old.txt",
+	}
+	if diff := cmp.Diff(wantDiff, goldenDiff(want, got)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVerifyGoldenCommand_Error(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{
+			name:    "no library",
+			args:    []string{"librarian", "verify-golden", "--golden", "golden.tar.gz"},
+			wantErr: errMissingLibrary,
+		},
+		{
+			name:    "no golden",
+			args:    []string{"librarian", "verify-golden", "library-one"},
+			wantErr: errMissingGolden,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			err := Run(t.Context(), test.args...)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/librarian/golang"
+	"github.com/urfave/cli/v3"
+)
+
+var errVersionDrift = errors.New("library versions differ from generated code")
+
+func verifyVersionsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "verify-versions",
+		Usage:     "check that generated code embeds the configured library versions",
+		UsageText: "librarian verify-versions [library]",
+		Description: `verify-versions compares the version of each library in librarian.yaml with
+the version embedded in its generated code, and fails if any of them differ.
+This happens when a version is changed in librarian.yaml without regenerating
+the library. If a library name is given, only that library is checked.
+Libraries without a version are skipped, and a library with a version whose
+generated code embeds none is reported as drifted.
+
+Only Go libraries are supported, whose version is the Version constant in
+<library>/internal/version.go.`,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfg, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+			return runVerifyVersions(cfg, cmd.Args().First())
+		},
+	}
+}
+
+func runVerifyVersions(cfg *config.Config, libraryName string) error {
+	if cfg.Language != languageGo {
+		return fmt.Errorf("%q does not support verify-versions", cfg.Language)
+	}
+	libraries := cfg.Libraries
+	if libraryName != "" {
+		lib, err := findLibrary(cfg, libraryName)
+		if err != nil {
+			return err
+		}
+		libraries = []*config.Library{lib}
+	}
+	var drifts []string
+	for _, lib := range libraries {
+		if lib.Version == "" {
+			continue
+		}
+		got, err := golang.EmbeddedVersion(libraryDir(cfg.Language, lib, cfg.Default))
+		if errors.Is(err, fs.ErrNotExist) {
+			drifts = append(drifts, fmt.Sprintf("  %s: librarian.yaml has %s, generated code has no version", lib.Name, lib.Version))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("library %q: %w", lib.Name, err)
+		}
+		if got != lib.Version {
+			drifts = append(drifts, fmt.Sprintf("  %s: librarian.yaml has %s, generated code has %s", lib.Name, lib.Version, got))
+		}
+	}
+	if len(drifts) > 0 {
+		return fmt.Errorf("%w:\n%s", errVersionDrift, strings.Join(drifts, "\n"))
+	}
+	return nil
+}
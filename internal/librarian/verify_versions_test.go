@@ -0,0 +1,136 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarian
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/librarian/golang"
+	"github.com/googleapis/librarian/internal/sample"
+)
+
+func writeVersionFile(t *testing.T, dir, version string) {
+	t.Helper()
+	path := filepath.Join(dir, golang.VersionFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := fmt.Sprintf("package internal\n\n// Version is the current tagged release of the library.\nconst Version = %q\n", version)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyVersionsCommand(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		args       []string
+		wantErr    error
+		wantDrifts []string
+	}{
+		{
+			name:       "all libraries",
+			args:       []string{"librarian", "verify-versions"},
+			wantErr:    errVersionDrift,
+			wantDrifts: []string{"secretmanager: librarian.yaml has 1.3.0, generated code has 1.2.0"},
+		},
+		{
+			name: "library in sync",
+			args: []string{"librarian", "verify-versions", "pubsub"},
+		},
+		{
+			name:       "library without embedded version",
+			args:       []string{"librarian", "verify-versions", "storage"},
+			wantErr:    errVersionDrift,
+			wantDrifts: []string{"storage: librarian.yaml has 1.0.0, generated code has no version"},
+		},
+		{
+			name: "library without version",
+			args: []string{"librarian", "verify-versions", "bigquery"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			t.Chdir(tempDir)
+			configContent := fmt.Sprintf(`language: go
+version: %s
+sources:
+  googleapis:
+    commit: 94ccedca05acb0bb60780789e93371c9e4100ddc
+    sha256: fff40946e897d96bbdccd566cb993048a87029b7e08eacee3fe99eac792721ba
+default:
+  output: .
+libraries:
+  - name: bigquery
+  - name: pubsub
+    version: 2.0.0
+  - name: secretmanager
+    version: 1.3.0
+  - name: storage
+    version: 1.0.0
+`, sample.LibrarianVersion)
+			if err := os.WriteFile(librarianConfigPath, []byte(configContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+			writeVersionFile(t, "pubsub", "2.0.0")
+			writeVersionFile(t, "secretmanager", "1.2.0")
+
+			err := Run(t.Context(), test.args...)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("want error %v, got %v", test.wantErr, err)
+			}
+			for _, drift := range test.wantDrifts {
+				if !strings.Contains(err.Error(), drift) {
+					t.Errorf("error does not report %q:\n%v", drift, err)
+				}
+			}
+			if err != nil && strings.Contains(err.Error(), "pubsub") {
+				t.Errorf("library in sync reported as drifted:\n%v", err)
+			}
+		})
+	}
+}
+
+func TestRunVerifyVersions_Error(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		cfg         *config.Config
+		libraryName string
+		wantErr     error
+	}{
+		{
+			name:        "unknown library",
+			cfg:         &config.Config{Language: languageGo},
+			libraryName: "missing",
+			wantErr:     ErrLibraryNotFound,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := runVerifyVersions(test.cfg, test.libraryName)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+	if err := runVerifyVersions(&config.Config{Language: languageRust}, ""); err == nil {
+		t.Error("expected an error for an unsupported language")
+	}
+}
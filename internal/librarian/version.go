@@ -19,17 +19,19 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 	"time"
 
 	"github.com/googleapis/librarian/internal/config"
-	"github.com/googleapis/librarian/internal/yaml"
+	"golang.org/x/mod/semver"
 )
 
 var (
-	errNoConfigVersion = errors.New("librarian.yaml does not specify a version")
-	errVersionMismatch = errors.New("version mismatch")
+	errNoConfigVersion    = errors.New("librarian.yaml does not specify a version")
+	errVersionMismatch    = errors.New("version mismatch")
+	errConfigVersionNewer = errors.New("librarian.yaml requires a newer librarian")
 )
 
 //go:embed version.txt
@@ -77,10 +79,13 @@ func version(info *debug.BuildInfo) string {
 // if the -f flag is set or if the binary version is "not available", which
 // occurs during local development without VCS info.
 func loadConfig(ctx context.Context) (*config.Config, error) {
-	cfg, err := yaml.Read[config.Config](librarianConfigPath)
+	cfg, err := readConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", errConfigNotFound, err)
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	if !skipVersionCheck(ctx) {
 		if err := compareVersions(cfg.Version, Version()); err != nil {
 			return nil, err
@@ -89,6 +94,87 @@ func loadConfig(ctx context.Context) (*config.Config, error) {
 	return cfg, nil
 }
 
+// readConfig reads the configuration file and resolves the relative paths in
+// it against the directory of that file.
+func readConfig(ctx context.Context) (*config.Config, error) {
+	path := configPath(ctx)
+	cfg, err := config.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	resolveConfigPaths(cfg, filepath.Dir(path))
+	return cfg, nil
+}
+
+// writeConfig writes cfg to path, with the paths resolved by readConfig made
+// relative to the directory of path again.
+func writeConfig(path string, cfg *config.Config) error {
+	dir := filepath.Dir(path)
+	relativizeConfigPaths(cfg, dir)
+	defer resolveConfigPaths(cfg, dir)
+	return config.Write(path, cfg)
+}
+
+// resolveConfigPaths joins dir to the relative paths in cfg.
+func resolveConfigPaths(cfg *config.Config, dir string) {
+	if dir == "." {
+		return
+	}
+	for _, p := range configPaths(cfg) {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+}
+
+// relativizeConfigPaths reverses resolveConfigPaths.
+func relativizeConfigPaths(cfg *config.Config, dir string) {
+	if dir == "." {
+		return
+	}
+	for _, p := range configPaths(cfg) {
+		if *p == filepath.Clean(dir) {
+			*p = "."
+		} else if rel, ok := strings.CutPrefix(*p, filepath.Clean(dir)+string(filepath.Separator)); ok {
+			*p = rel
+		}
+	}
+}
+
+// configPaths returns the fields of cfg that hold paths relative to the
+// configuration file.
+func configPaths(cfg *config.Config) []*string {
+	var paths []*string
+	if cfg.Sources != nil {
+		for _, source := range []*config.Source{
+			cfg.Sources.Conformance,
+			cfg.Sources.Discovery,
+			cfg.Sources.Googleapis,
+			cfg.Sources.ProtobufSrc,
+			cfg.Sources.Showcase,
+		} {
+			if source != nil {
+				paths = append(paths, &source.Dir)
+			}
+		}
+	}
+	if cfg.Default != nil {
+		paths = append(paths, &cfg.Default.Output)
+	}
+	if cfg.Release != nil {
+		paths = append(paths, &cfg.Release.RootsPem)
+	}
+	for _, lib := range cfg.Libraries {
+		paths = append(paths, &lib.Output)
+	}
+	return paths
+}
+
+// compareVersions returns an error unless configVersion and binaryVersion
+// are the same. When both are valid semantic versions and the configuration
+// asks for a newer version than the binary, the error wraps
+// errConfigVersionNewer, as the configuration may use features this binary
+// does not know about.
 func compareVersions(configVersion, binaryVersion string) error {
 	if configVersion == "" {
 		return errNoConfigVersion
@@ -97,6 +183,12 @@ func compareVersions(configVersion, binaryVersion string) error {
 	if binaryVersion == versionNotAvailable {
 		return nil
 	}
+	if semver.IsValid(configVersion) && semver.IsValid(binaryVersion) && semver.Compare(configVersion, binaryVersion) > 0 {
+		return fmt.Errorf(`%w: librarian.yaml version %s is newer than binary version %s
+	go run github.com/googleapis/librarian/cmd/librarian@%s
+    or use -f to skip this check`,
+			errConfigVersionNewer, configVersion, binaryVersion, configVersion)
+	}
 	if configVersion != binaryVersion {
 		return fmt.Errorf(`%w: binary version %s does not match librarian.yaml version %s
 	go run github.com/googleapis/librarian/cmd/librarian@%s
@@ -150,3 +242,12 @@ func skipVersionCheck(ctx context.Context) bool {
 	v, _ := ctx.Value(skipVersionCheckKey{}).(bool)
 	return v
 }
+
+// configPath returns the path of the librarian configuration file set by the
+// --config flag, or librarianConfigPath if the flag was not set.
+func configPath(ctx context.Context) string {
+	if v, _ := ctx.Value(configPathKey{}).(string); v != "" {
+		return v
+	}
+	return librarianConfigPath
+}
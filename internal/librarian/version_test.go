@@ -18,9 +18,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/config"
+	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/yaml"
 )
 
 func TestVersion(t *testing.T) {
@@ -167,11 +174,29 @@ func TestCompareVersions(t *testing.T) {
 			binaryVersion: "v1.0.0",
 		},
 		{
-			name:          "mismatched versions",
+			name:          "older config version",
 			configVersion: "v1.0.0",
 			binaryVersion: "v2.0.0",
 			wantErr:       errVersionMismatch,
 		},
+		{
+			name:          "newer config version",
+			configVersion: "v1.3.0",
+			binaryVersion: "v1.2.0",
+			wantErr:       errConfigVersionNewer,
+		},
+		{
+			name:          "newer config version than pseudo-version",
+			configVersion: "v1.3.0",
+			binaryVersion: "v1.2.1-0.20260101000000-abcdef123456",
+			wantErr:       errConfigVersionNewer,
+		},
+		{
+			name:          "non-semver versions",
+			configVersion: "latest",
+			binaryVersion: "v1.2.0",
+			wantErr:       errVersionMismatch,
+		},
 		{
 			name:          "local build skips check",
 			configVersion: "v1.0.0",
@@ -229,3 +254,69 @@ func TestSkipVersionCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfig_InvalidTransport(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Chdir(tempDir)
+	cfg := sample.Config()
+	cfg.Libraries[0].Transport = "grcp+rest"
+	if err := yaml.Write(filepath.Join(tempDir, librarianConfigPath), cfg); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(t.Context(), skipVersionCheckKey{}, true)
+	if _, err := loadConfig(ctx); !errors.Is(err, config.ErrInvalidTransport) {
+		t.Errorf("want error %v, got %v", config.ErrInvalidTransport, err)
+	}
+}
+
+func TestReadWriteConfig_RelativePaths(t *testing.T) {
+	t.Chdir(t.TempDir())
+	path := filepath.Join("path", "to", librarianConfigPath)
+	absDir, err := filepath.Abs("googleapis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sample.Config()
+	want.Sources.Googleapis.Dir = absDir
+	want.Sources.Showcase = &config.Source{Dir: "showcase"}
+	want.Default.Output = "."
+	want.Libraries[0].Output = "output"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Write(path, want); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(t.Context(), configPathKey{}, path)
+
+	got, err := readConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, check := range []struct {
+		name, want, got string
+	}{
+		{"googleapis", absDir, got.Sources.Googleapis.Dir},
+		{"showcase", filepath.Join("path", "to", "showcase"), got.Sources.Showcase.Dir},
+		{"default output", filepath.Join("path", "to"), got.Default.Output},
+		{"library output", filepath.Join("path", "to", "output"), got.Libraries[0].Output},
+	} {
+		if check.got != check.want {
+			t.Errorf("%s: want %q, got %q", check.name, check.want, check.got)
+		}
+	}
+
+	if err := writeConfig(path, got); err != nil {
+		t.Fatal(err)
+	}
+	written, err := config.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, written); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	if got.Libraries[0].Output != filepath.Join("path", "to", "output") {
+		t.Errorf("writeConfig did not restore resolved paths, got %q", got.Libraries[0].Output)
+	}
+}
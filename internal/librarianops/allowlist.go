@@ -0,0 +1,252 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarianops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/librarian/internal/config/bazel"
+	"github.com/googleapis/librarian/internal/serviceconfig"
+	"github.com/urfave/cli/v3"
+)
+
+const formatMarkdown = "md"
+
+var (
+	errUnsupportedFormat = errors.New("unsupported format")
+	errMissingFiles      = errors.New("allowlist references missing files")
+	errNoServiceConfig   = errors.New("no service config found")
+)
+
+func dumpAllowlistCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "dump-allowlist",
+		Usage:     "print the API allowlist",
+		UsageText: "librarianops dump-allowlist [--format md]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "output `format`; only md (Markdown) is supported",
+				Value: formatMarkdown,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if format := cmd.String("format"); format != formatMarkdown {
+				return fmt.Errorf("%w: %q", errUnsupportedFormat, format)
+			}
+			return writeAllowlistMarkdown(cmd.Root().Writer, serviceconfig.APIs)
+		},
+	}
+}
+
+func addAPICommand() *cli.Command {
+	return &cli.Command{
+		Name:      "add-api",
+		Usage:     "print a new API allowlist entry",
+		UsageText: "librarianops add-api --googleapis <dir> --path <api-path>",
+		Description: `Reads the service config and BUILD.bazel of the API at path and prints an
+entry for the APIs slice in internal/serviceconfig/api.go, with the service
+name and the transport of each language filled in.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "googleapis",
+				Usage:    "googleapis `directory`",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "path",
+				Usage:    "API `path` in googleapis, such as google/cloud/secretmanager/v1",
+				Required: true,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			api, err := newAPIEntry(cmd.String("googleapis"), cmd.String("path"))
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.Root().Writer, formatAPIEntry(api))
+			return err
+		},
+	}
+}
+
+// newAPIEntry returns the allowlist entry for the API at path, deriving the
+// service name from its service config and the transports from its
+// BUILD.bazel file.
+func newAPIEntry(googleapisDir, path string) (serviceconfig.API, error) {
+	serviceConfig, err := serviceconfig.FindServiceConfig(googleapisDir, path)
+	if err != nil {
+		return serviceconfig.API{}, err
+	}
+	if serviceConfig == "" {
+		return serviceconfig.API{}, fmt.Errorf("%w in %s", errNoServiceConfig, path)
+	}
+	service, err := serviceconfig.Read(filepath.Join(googleapisDir, serviceConfig))
+	if err != nil {
+		return serviceconfig.API{}, err
+	}
+	transports, err := bazel.Transports(filepath.Join(googleapisDir, path, "BUILD.bazel"))
+	if err != nil {
+		return serviceconfig.API{}, err
+	}
+	api := serviceconfig.API{
+		Path:        path,
+		ServiceName: service.GetName(),
+	}
+	if len(transports) > 0 {
+		api.Transports = transports
+	}
+	return api, nil
+}
+
+// formatAPIEntry returns api as a Go composite literal for the APIs slice,
+// omitting the fields set by add-api that are empty.
+func formatAPIEntry(api serviceconfig.API) string {
+	fields := []string{fmt.Sprintf("Path: %q", api.Path)}
+	if api.ServiceName != "" {
+		fields = append(fields, fmt.Sprintf("ServiceName: %q", api.ServiceName))
+	}
+	if len(api.Transports) > 0 {
+		var transports []string
+		for _, language := range slices.Sorted(maps.Keys(api.Transports)) {
+			transports = append(transports, fmt.Sprintf("%q: %q", language, api.Transports[language]))
+		}
+		fields = append(fields, fmt.Sprintf("Transports: map[string]string{%s}", strings.Join(transports, ", ")))
+	}
+	return fmt.Sprintf("{%s},", strings.Join(fields, ", "))
+}
+
+func validateAllowlistCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "validate-allowlist",
+		Usage:       "check that files referenced by the API allowlist exist",
+		UsageText:   "librarianops validate-allowlist [--googleapis <dir>] [--discovery <dir>] [--testdata <dir>]",
+		Description: "References relative to a root that is not specified are not checked. Languages whose transport for an API breaks a transport consistency rule are logged as warnings.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "googleapis",
+				Usage: "googleapis `directory`, the root of ServiceConfig paths",
+			},
+			&cli.StringFlag{
+				Name:  "discovery",
+				Usage: "discovery-artifact-manager `directory`, the root of Discovery paths",
+			},
+			&cli.StringFlag{
+				Name:  "testdata",
+				Usage: "`directory` that is the root of OpenAPI paths, such as internal in this repository",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			roots := allowlistRoots{
+				googleapis: cmd.String("googleapis"),
+				discovery:  cmd.String("discovery"),
+				testdata:   cmd.String("testdata"),
+			}
+			warnTransports(serviceconfig.APIs, serviceconfig.TransportConsistency)
+			return validateAllowlist(serviceconfig.APIs, roots)
+		},
+	}
+}
+
+// allowlistRoots are the directories that files referenced by the allowlist
+// are relative to. An empty root disables checking the files relative to it.
+type allowlistRoots struct {
+	// googleapis is the root for API.ServiceConfig.
+	googleapis string
+	// discovery is the root for API.Discovery.
+	discovery string
+	// testdata is the root for API.OpenAPI.
+	testdata string
+}
+
+// validateAllowlist checks that no two apis share a service name and that
+// every ServiceConfig, Discovery and OpenAPI file referenced by apis exists
+// under its root. The error lists every missing file along with the API path
+// referencing it.
+func validateAllowlist(apis []serviceconfig.API, roots allowlistRoots) error {
+	var missing []string
+	for _, api := range apis {
+		for _, ref := range []struct {
+			field, root, file string
+		}{
+			{"service config", roots.googleapis, api.ServiceConfig},
+			{"discovery", roots.discovery, api.Discovery},
+			{"openapi", roots.testdata, api.OpenAPI},
+		} {
+			if ref.root == "" || ref.file == "" {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(ref.root, ref.file)); err != nil {
+				missing = append(missing, fmt.Sprintf("%s: %s %s", api.Path, ref.field, ref.file))
+			}
+		}
+	}
+	err := serviceconfig.ValidateServiceNames(apis)
+	if len(missing) > 0 {
+		err = errors.Join(err, fmt.Errorf("%w:\n  %s", errMissingFiles, strings.Join(missing, "\n  ")))
+	}
+	return err
+}
+
+// warnTransports logs a warning for every language whose transport for one of
+// apis breaks rules.
+func warnTransports(apis []serviceconfig.API, rules []serviceconfig.TransportRule) {
+	for _, v := range serviceconfig.CheckTransports(apis, rules) {
+		slog.Warn("transport breaks transport consistency rule", "api", v.Path, "language", v.Language, "transport", v.Transport, "want", v.Want)
+	}
+}
+
+// writeAllowlistMarkdown writes apis to w as a Markdown table, with one row
+// per API.
+func writeAllowlistMarkdown(w io.Writer, apis []serviceconfig.API) error {
+	var b strings.Builder
+	b.WriteString("| Path | Languages | Transports | Discovery | OpenAPI | Service Config | Service Name | Title |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, api := range apis {
+		languages := "all"
+		if len(api.Languages) > 0 {
+			languages = strings.Join(api.Languages, ", ")
+		}
+		var transports []string
+		for _, language := range slices.Sorted(maps.Keys(api.Transports)) {
+			transports = append(transports, language+": "+api.Transports[language])
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(api.Path),
+			markdownCell(languages),
+			markdownCell(strings.Join(transports, ", ")),
+			markdownCell(api.Discovery),
+			markdownCell(api.OpenAPI),
+			markdownCell(api.ServiceConfig),
+			markdownCell(api.ServiceName),
+			markdownCell(api.Title))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes pipes, which would otherwise end a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package librarianops
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/librarian/internal/serviceconfig"
+)
+
+func TestWriteAllowlistMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := writeAllowlistMarkdown(&b, serviceconfig.APIs); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"| Path | Languages | Transports | Discovery | OpenAPI | Service Config | Service Name | Title |\n",
+		"| google/api/apikeys/v2 | all |  |  |  |  |  |  |\n",
+		"| google/ads/admanager/v1 | python |  |  |  |  |  |  |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rendered allowlist is missing %q", want)
+		}
+	}
+}
+
+func TestWriteAllowlistMarkdown_Escaping(t *testing.T) {
+	var b strings.Builder
+	apis := []serviceconfig.API{
+		{
+			Path:        "google/example/v1",
+			Languages:   []string{"go", "rust"},
+			ServiceName: "example.googleapis.com",
+			Title:       "A | B",
+			Transports:  map[string]string{"rust": "rest", "go": "grpc"},
+		},
+	}
+	if err := writeAllowlistMarkdown(&b, apis); err != nil {
+		t.Fatal(err)
+	}
+	want := `| Path | Languages | Transports | Discovery | OpenAPI | Service Config | Service Name | Title |
+| --- | --- | --- | --- | --- | --- | --- | --- |
+| google/example/v1 | go, rust | go: grpc, rust: rest |  |  |  | example.googleapis.com | A \| B |
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDumpAllowlist_UnsupportedFormat(t *testing.T) {
+	err := Run(t.Context(), "librarianops", "dump-allowlist", "--format", "json")
+	if !errors.Is(err, errUnsupportedFormat) {
+		t.Errorf("Run() error = %v, want %v", err, errUnsupportedFormat)
+	}
+}
+
+func TestValidateAllowlist(t *testing.T) {
+	// The OpenAPI specs referenced by the allowlist live in internal/testdata.
+	if err := validateAllowlist(serviceconfig.APIs, allowlistRoots{testdata: ".."}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateAllowlist_Missing(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{
+		filepath.Join("googleapis", "google", "cloud", "foo", "v1", "foo_v1.yaml"),
+		filepath.Join("discovery", "discoveries", "foo.v1.json"),
+	} {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apis := []serviceconfig.API{
+		{Path: "google/cloud/foo/v1", ServiceConfig: "google/cloud/foo/v1/foo_v1.yaml"},
+		{Path: "google/cloud/foo/v1", Discovery: "discoveries/foo.v1.json"},
+		{Path: "google/cloud/bar/v1", OpenAPI: "testdata/bar_openapi_v1.json"},
+	}
+	roots := allowlistRoots{
+		googleapis: filepath.Join(root, "googleapis"),
+		discovery:  filepath.Join(root, "discovery"),
+		testdata:   root,
+	}
+	err := validateAllowlist(apis, roots)
+	if !errors.Is(err, errMissingFiles) {
+		t.Fatalf("want error %v, got %v", errMissingFiles, err)
+	}
+	want := "google/cloud/bar/v1: openapi testdata/bar_openapi_v1.json"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+	if strings.Contains(err.Error(), "foo") {
+		t.Errorf("error %q reports an existing file", err)
+	}
+}
+
+func TestValidateAllowlist_DuplicateServiceName(t *testing.T) {
+	apis := []serviceconfig.API{
+		{Path: "google/cloud/foo/v1", ServiceName: "foo.googleapis.com"},
+		{Path: "google/cloud/foo/v1beta", ServiceName: "foo.googleapis.com"},
+	}
+	err := validateAllowlist(apis, allowlistRoots{})
+	if !errors.Is(err, serviceconfig.ErrDuplicateServiceName) {
+		t.Errorf("want error %v, got %v", serviceconfig.ErrDuplicateServiceName, err)
+	}
+}
+
+func TestNewAPIEntry(t *testing.T) {
+	googleapisDir := t.TempDir()
+	apiDir := filepath.Join(googleapisDir, "google", "cloud", "foo", "v1")
+	if err := os.MkdirAll(apiDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"foo_v1.yaml": `type: google.api.Service
+config_version: 3
+name: foo.googleapis.com
+title: Foo API
+`,
+		"foo_gapic.yaml": "type: com.google.api.codegen.ConfigProto\n",
+		"BUILD.bazel": `
+go_gapic_library(
+    name = "foo_go_gapic",
+    importpath = "cloud.google.com/go/foo/apiv1;foo",
+    service_yaml = "foo_v1.yaml",
+    transport = "grpc+rest",
+)
+
+py_gapic_library(
+    name = "foo_py_gapic",
+    transport = "grpc",
+)
+`,
+	} {
+		if err := os.WriteFile(filepath.Join(apiDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	api, err := newAPIEntry(googleapisDir, "google/cloud/foo/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{Path: "google/cloud/foo/v1", ServiceName: "foo.googleapis.com", Transports: map[string]string{"go": "grpc+rest", "python": "grpc"}},`
+	if diff := cmp.Diff(want, formatAPIEntry(api)); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewAPIEntry_NoServiceConfig(t *testing.T) {
+	_, err := newAPIEntry(t.TempDir(), "google/cloud/foo/v1")
+	if !errors.Is(err, errNoServiceConfig) {
+		t.Errorf("want error %v, got %v", errNoServiceConfig, err)
+	}
+}
+
+func TestWarnTransports(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	apis := []serviceconfig.API{
+		{
+			Path:       "google/cloud/bigquery/storage/v1",
+			Transports: map[string]string{"go": "grpc", "python": "grpc", "rust": "rest"},
+		},
+	}
+	warnTransports(apis, serviceconfig.TransportConsistency)
+	want := `level=WARN msg="transport breaks transport consistency rule" api=google/cloud/bigquery/storage/v1 language=rust transport=rest want=grpc` + "\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -19,12 +19,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
-	"github.com/googleapis/librarian/internal/yaml"
+	"github.com/googleapis/librarian/internal/librarian"
 	"github.com/urfave/cli/v3"
 )
 
@@ -33,6 +37,28 @@ const (
 	commitTitle  = "chore: run librarian update and generate --all"
 )
 
+const (
+	defaultPRTitle = "chore: update librarian, {{.Sources}}, and regenerate"
+	defaultPRBody  = `Update librarian version to @main ({{.Version}}).
+
+Update {{.Sources}} to the latest commit and regenerate all client libraries.`
+)
+
+// pullRequestData is the data available to the config.PullRequest templates.
+type pullRequestData struct {
+	// Timestamp is the time the run started.
+	Timestamp time.Time
+	// Branch is the name of the branch holding the changes.
+	Branch string
+	// Libraries are the names of the libraries with changes.
+	Libraries []string
+	// Version is the librarian version used to regenerate.
+	Version string
+	// Sources describes the updated source repositories, such as
+	// "googleapis".
+	Sources string
+}
+
 func generateCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "generate",
@@ -124,7 +150,9 @@ func processRepo(ctx context.Context, repoName, repoDir string, verbose bool) (e
 	}
 	defer os.Chdir(originalWD)
 
-	if err := createBranch(ctx, time.Now()); err != nil {
+	now := time.Now()
+	branch := branchName(now)
+	if err := createBranch(ctx, branch); err != nil {
 		return err
 	}
 	version, err := getLibrarianVersionAtMain(ctx)
@@ -156,10 +184,16 @@ func processRepo(ctx context.Context, repoName, repoDir string, verbose bool) (e
 		return err
 	}
 	if repoName != repoFake {
+		// Render the pull request before pushing, so that a bad template
+		// does not leave a branch behind without a pull request.
+		title, body, err := pullRequestText(ctx, repoName, repoDir, version, branch, now)
+		if err != nil {
+			return err
+		}
 		if err := pushBranch(ctx); err != nil {
 			return err
 		}
-		if err := createPR(ctx, repoName, version); err != nil {
+		if err := createPR(ctx, title, body); err != nil {
 			return err
 		}
 	}
@@ -170,9 +204,12 @@ func cloneRepo(ctx context.Context, repoDir, repoName string) error {
 	return command.Run(ctx, "gh", "repo", "clone", fmt.Sprintf("googleapis/%s", repoName), repoDir)
 }
 
-func createBranch(ctx context.Context, now time.Time) error {
-	branchName := fmt.Sprintf("%s%s", branchPrefix, now.Format("2006-01-02"))
-	return command.Run(ctx, "git", "checkout", "-b", branchName)
+func branchName(now time.Time) string {
+	return fmt.Sprintf("%s%s", branchPrefix, now.Format("2006-01-02"))
+}
+
+func createBranch(ctx context.Context, branch string) error {
+	return command.Run(ctx, "git", "checkout", "-b", branch)
 }
 
 func commitChanges(ctx context.Context) error {
@@ -186,18 +223,95 @@ func pushBranch(ctx context.Context) error {
 	return command.Run(ctx, "git", "push", "-u", "origin", "HEAD")
 }
 
-func createPR(ctx context.Context, repoName, librarianVersion string) error {
+// pullRequestText returns the title and body of the pull request for the
+// changes committed to branch.
+func pullRequestText(ctx context.Context, repoName, repoDir, librarianVersion, branch string, now time.Time) (title, body string, err error) {
+	cfg, err := config.Read(filepath.Join(repoDir, "librarian.yaml"))
+	if err != nil {
+		return "", "", err
+	}
 	sources := "googleapis"
 	if repoName == repoRust {
 		sources = "googleapis and discovery-artifact-manager"
 	}
-	title := fmt.Sprintf("chore: update librarian, %s, and regenerate", sources)
-	body := fmt.Sprintf(`Update librarian version to @main (%s).
+	libraries, err := changedLibraries(ctx, cfg)
+	if err != nil {
+		return "", "", err
+	}
+	data := &pullRequestData{
+		Timestamp: now,
+		Branch:    branch,
+		Libraries: libraries,
+		Version:   librarianVersion,
+		Sources:   sources,
+	}
+	return renderPullRequest(cfg.PullRequest, data)
+}
 
-Update %s to the latest commit and regenerate all client libraries.`, librarianVersion, sources)
+// changedLibraries returns the names of the libraries in cfg whose files were
+// changed by the last commit.
+func changedLibraries(ctx context.Context, cfg *config.Config) ([]string, error) {
+	output, err := command.Output(ctx, "git", "diff", "--name-only", "HEAD~1", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	files := strings.Fields(output)
+	var names []string
+	for _, lib := range cfg.Libraries {
+		dir := librarian.LibraryDir(cfg, lib)
+		if dir == "" {
+			continue
+		}
+		if slices.ContainsFunc(files, func(f string) bool { return isInDir(f, dir) }) {
+			names = append(names, lib.Name)
+		}
+	}
+	return names, nil
+}
+
+// isInDir reports whether the slash-separated file is inside dir.
+func isInDir(file, dir string) bool {
+	dir = path.Clean(filepath.ToSlash(dir))
+	return dir == "." || strings.HasPrefix(file, dir+"/")
+}
+
+func createPR(ctx context.Context, title, body string) error {
 	return command.Run(ctx, "gh", "pr", "create", "--title", title, "--body", body)
 }
 
+// renderPullRequest executes the title and body templates in pr with data,
+// falling back to the default templates for any that are not set.
+func renderPullRequest(pr *config.PullRequest, data *pullRequestData) (title, body string, err error) {
+	titleTmpl, bodyTmpl := defaultPRTitle, defaultPRBody
+	if pr != nil && pr.Title != "" {
+		titleTmpl = pr.Title
+	}
+	if pr != nil && pr.Body != "" {
+		bodyTmpl = pr.Body
+	}
+	title, err = renderTemplate("title", titleTmpl, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err = renderTemplate("body", bodyTmpl, data)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(title), body, nil
+}
+
+func renderTemplate(name, text string, data *pullRequestData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing pull request %s template: %w", name, err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("executing pull request %s template: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
 func runCargoUpdate(ctx context.Context) error {
 	return command.Run(ctx, "cargo", "update", "--workspace")
 }
@@ -221,12 +335,12 @@ func getLibrarianVersionAtMain(ctx context.Context) (string, error) {
 
 func updateLibrarianVersion(version, repoDir string) error {
 	configPath := filepath.Join(repoDir, "librarian.yaml")
-	cfg, err := yaml.Read[config.Config](configPath)
+	cfg, err := config.Read(configPath)
 	if err != nil {
 		return err
 	}
 	cfg.Version = version
-	return yaml.Write(configPath, cfg)
+	return config.Write(configPath, cfg)
 }
 
 func runLibrarianWithVersion(ctx context.Context, version string, verbose bool, args ...string) error {
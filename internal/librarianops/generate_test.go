@@ -21,11 +21,13 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/librarian/internal/command"
 	"github.com/googleapis/librarian/internal/config"
 	"github.com/googleapis/librarian/internal/sample"
+	"github.com/googleapis/librarian/internal/testhelper"
 	"github.com/googleapis/librarian/internal/yaml"
 )
 
@@ -214,3 +216,128 @@ func TestVerboseFlagSetsCommandVerbose(t *testing.T) {
 		})
 	}
 }
+
+func TestChangedLibraries(t *testing.T) {
+	testhelper.ContinueInNewGitRepository(t, t.TempDir())
+	commit := func(files ...string) {
+		t.Helper()
+		for _, file := range files {
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(file, []byte(file), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := command.Run(t.Context(), "git", "add", "."); err != nil {
+			t.Fatal(err)
+		}
+		if err := command.Run(t.Context(), "git", "commit", "-m", "regenerate"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("README.md")
+	commit("secretmanager/apiv1/client.go", "speech/apiv1/client.go")
+	cfg := &config.Config{
+		Language: "go",
+		Default:  &config.Default{Output: "."},
+		Libraries: []*config.Library{
+			{Name: "pubsub"},
+			{Name: "secretmanager"},
+			{Name: "speech"},
+		},
+	}
+	got, err := changedLibraries(t.Context(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"secretmanager", "speech"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenderPullRequest(t *testing.T) {
+	data := &pullRequestData{
+		Timestamp: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Branch:    "librarianops-generateall-2026-03-04",
+		Libraries: []string{"google-cloud-secretmanager", "google-cloud-speech"},
+		Version:   "v0.8.1-0.20260304050607-abcdef123456",
+		Sources:   "googleapis",
+	}
+	for _, test := range []struct {
+		name      string
+		pr        *config.PullRequest
+		wantTitle string
+		wantBody  string
+	}{
+		{
+			name:      "default",
+			wantTitle: "chore: update librarian, googleapis, and regenerate",
+			wantBody: `Update librarian version to @main (v0.8.1-0.20260304050607-abcdef123456).
+
+Update googleapis to the latest commit and regenerate all client libraries.`,
+		},
+		{
+			name: "configured",
+			pr: &config.PullRequest{
+				Title: `feat: API regeneration: {{.Timestamp.Format "20060102T150405Z"}}`,
+				Body: `Branch: {{.Branch}}
+
+Regenerated libraries:
+{{range .Libraries}}- {{.}}
+{{end}}`,
+			},
+			wantTitle: "feat: API regeneration: 20260304T050607Z",
+			wantBody: `Branch: librarianops-generateall-2026-03-04
+
+Regenerated libraries:
+- google-cloud-secretmanager
+- google-cloud-speech
+`,
+		},
+		{
+			name:      "only title configured",
+			pr:        &config.PullRequest{Title: "chore: regenerate {{len .Libraries}} libraries"},
+			wantTitle: "chore: regenerate 2 libraries",
+			wantBody: `Update librarian version to @main (v0.8.1-0.20260304050607-abcdef123456).
+
+Update googleapis to the latest commit and regenerate all client libraries.`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			gotTitle, gotBody, err := renderPullRequest(test.pr, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.wantTitle, gotTitle); diff != "" {
+				t.Errorf("title mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantBody, gotBody); diff != "" {
+				t.Errorf("body mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderPullRequest_Error(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		pr   *config.PullRequest
+	}{
+		{
+			name: "invalid title",
+			pr:   &config.PullRequest{Title: "{{.Branch"},
+		},
+		{
+			name: "unknown field in body",
+			pr:   &config.PullRequest{Body: "{{.Unknown}}"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := renderPullRequest(test.pr, &pullRequestData{}); err == nil {
+				t.Error("renderPullRequest() succeeded, want error")
+			}
+		})
+	}
+}
@@ -39,7 +39,10 @@ func Run(ctx context.Context, args ...string) error {
 		Usage:     "orchestrate librarian operations across multiple repositories",
 		UsageText: "librarianops [command]",
 		Commands: []*cli.Command{
+			addAPICommand(),
+			dumpAllowlistCommand(),
 			generateCommand(),
+			validateAllowlistCommand(),
 		},
 	}
 	return cmd.Run(ctx, args)
@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package protoimports checks that the imports of a set of proto files can be
+// resolved before they are handed to protoc.
+package protoimports
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// wellKnownPrefix is the import prefix of the well-known types, which protoc
+// bundles and resolves without any include path.
+const wellKnownPrefix = "google/protobuf/"
+
+// ErrUnresolved is returned when one or more imports cannot be resolved.
+var ErrUnresolved = errors.New("unresolved proto imports")
+
+var importRegex = regexp.MustCompile(`^\s*import\s+(?:(?:public|weak)\s+)?"([^"]+)"\s*;`)
+
+// Unresolved is an import that could not be found in any include directory.
+type Unresolved struct {
+	// Import is the path as written in the import statement.
+	Import string
+	// ImportedBy is the proto file containing the import statement.
+	ImportedBy string
+}
+
+// Check follows the imports of files, transitively, and reports every import
+// that cannot be found in includeDirs. It returns an error wrapping
+// ErrUnresolved that lists each missing import alongside the proto that needs
+// it, so that all problems are reported at once instead of one per protoc run.
+func Check(includeDirs, files []string) error {
+	missing, err := Find(includeDirs, files)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	var lines []string
+	for _, m := range missing {
+		lines = append(lines, fmt.Sprintf("  %s (imported by %s)", m.Import, m.ImportedBy))
+	}
+	return fmt.Errorf("%w:\n%s", ErrUnresolved, strings.Join(lines, "\n"))
+}
+
+// Find follows the imports of files, transitively, and returns the imports
+// that cannot be found in includeDirs, in the order they were encountered.
+func Find(includeDirs, files []string) ([]Unresolved, error) {
+	var missing []Unresolved
+	visited := make(map[string]bool)
+	queue := append([]string(nil), files...)
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		if visited[file] {
+			continue
+		}
+		visited[file] = true
+		imports, err := parseImports(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range imports {
+			path, ok := resolve(includeDirs, imp)
+			if ok {
+				queue = append(queue, path)
+				continue
+			}
+			if strings.HasPrefix(imp, wellKnownPrefix) {
+				continue
+			}
+			missing = append(missing, Unresolved{Import: imp, ImportedBy: displayName(includeDirs, file)})
+		}
+	}
+	return missing, nil
+}
+
+// parseImports returns the paths imported by the proto file at path.
+func parseImports(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var imports []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := importRegex.FindStringSubmatch(scanner.Text()); m != nil {
+			imports = append(imports, m[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return imports, nil
+}
+
+// resolve returns the path of imp in the first include directory that
+// contains it.
+func resolve(includeDirs []string, imp string) (string, bool) {
+	for _, dir := range includeDirs {
+		path := filepath.Join(dir, filepath.FromSlash(imp))
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// displayName returns file relative to the include directory containing it,
+// matching the way protoc names files in its own errors.
+func displayName(includeDirs []string, file string) string {
+	for _, dir := range includeDirs {
+		rel, err := filepath.Rel(dir, file)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return file
+}
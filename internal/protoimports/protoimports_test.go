@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoimports
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeProtos(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		files map[string]string
+		want  []Unresolved
+	}{
+		{
+			name: "all resolved",
+			files: map[string]string{
+				"google/foo/v1/foo.proto": `syntax = "proto3";
+import "google/api/annotations.proto";
+import public "google/foo/v1/common.proto";
+import "google/protobuf/empty.proto";
+`,
+				"google/foo/v1/common.proto":   `syntax = "proto3";`,
+				"google/api/annotations.proto": `syntax = "proto3";`,
+			},
+		},
+		{
+			name: "missing import",
+			files: map[string]string{
+				"google/foo/v1/foo.proto": `syntax = "proto3";
+import "google/api/annotations.proto";
+import "google/type/missing.proto";
+`,
+				"google/api/annotations.proto": `syntax = "proto3";`,
+			},
+			want: []Unresolved{
+				{Import: "google/type/missing.proto", ImportedBy: "google/foo/v1/foo.proto"},
+			},
+		},
+		{
+			name: "missing transitive import",
+			files: map[string]string{
+				"google/foo/v1/foo.proto": `syntax = "proto3";
+import weak "google/foo/v1/common.proto";
+`,
+				"google/foo/v1/common.proto": `syntax = "proto3";
+import "google/rpc/missing.proto";
+`,
+			},
+			want: []Unresolved{
+				{Import: "google/rpc/missing.proto", ImportedBy: "google/foo/v1/common.proto"},
+			},
+		},
+		{
+			name: "commented import ignored",
+			files: map[string]string{
+				"google/foo/v1/foo.proto": `syntax = "proto3";
+// import "google/type/missing.proto";
+`,
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeProtos(t, dir, test.files)
+			got, err := Find([]string{dir}, []string{filepath.Join(dir, "google/foo/v1/foo.proto")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeProtos(t, dir, map[string]string{
+		"google/foo/v1/foo.proto": `syntax = "proto3";
+import "google/type/missing.proto";
+`,
+		"google/foo/v1/bar.proto": `syntax = "proto3";
+import "google/rpc/missing.proto";
+`,
+	})
+	err := Check([]string{dir}, []string{
+		filepath.Join(dir, "google/foo/v1/foo.proto"),
+		filepath.Join(dir, "google/foo/v1/bar.proto"),
+	})
+	if !errors.Is(err, ErrUnresolved) {
+		t.Fatalf("want error %v, got %v", ErrUnresolved, err)
+	}
+	for _, want := range []string{
+		"google/type/missing.proto (imported by google/foo/v1/foo.proto)",
+		"google/rpc/missing.proto (imported by google/foo/v1/bar.proto)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestCheck_Resolved(t *testing.T) {
+	dir := t.TempDir()
+	writeProtos(t, dir, map[string]string{
+		"google/foo/v1/foo.proto": `syntax = "proto3";
+import "google/protobuf/timestamp.proto";
+`,
+	})
+	if err := Check([]string{dir}, []string{filepath.Join(dir, "google/foo/v1/foo.proto")}); err != nil {
+		t.Fatal(err)
+	}
+}
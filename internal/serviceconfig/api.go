@@ -16,6 +16,17 @@
 
 package serviceconfig
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrDuplicateServiceName is returned by ValidateServiceNames when more than
+// one API has the same service name.
+var ErrDuplicateServiceName = errors.New("duplicate service name")
+
 const (
 	langPython = "python"
 	langRust   = "rust"
@@ -59,8 +70,39 @@ type API struct {
 	// If empty, the service config is discovered in the directory specified by Path.
 	ServiceConfig string
 
+	// ServiceName is the name of the service from the service config, such as
+	// "secretmanager.googleapis.com".
+	ServiceName string
+
 	// Title overrides the API title from the service config.
 	Title string
+
+	// Transports maps a language to the transport of its client library for
+	// this API, such as "grpc+rest" or "grpc", as declared in BUILD.bazel.
+	Transports map[string]string
+}
+
+// ValidateServiceNames returns an error listing every non-empty ServiceName
+// shared by more than one of apis, together with the paths of those APIs, as
+// looking up an API by such a service name would be ambiguous.
+func ValidateServiceNames(apis []API) error {
+	paths := make(map[string][]string)
+	for _, api := range apis {
+		if api.ServiceName != "" {
+			paths[api.ServiceName] = append(paths[api.ServiceName], api.Path)
+		}
+	}
+	var duplicates []string
+	for name, p := range paths {
+		if len(p) > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("%s (%s)", name, strings.Join(p, ", ")))
+		}
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	slices.Sort(duplicates)
+	return fmt.Errorf("%w: %s", ErrDuplicateServiceName, strings.Join(duplicates, "; "))
 }
 
 // APIs defines all API paths and their language availability.
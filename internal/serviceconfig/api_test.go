@@ -15,6 +15,8 @@
 package serviceconfig
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -37,3 +39,30 @@ func TestAPIsAlphabeticalOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestAPIsNoDuplicateServiceNames(t *testing.T) {
+	if err := ValidateServiceNames(APIs); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateServiceNames(t *testing.T) {
+	apis := []API{
+		{Path: "google/cloud/foo/v1", ServiceName: "foo.googleapis.com"},
+		{Path: "google/cloud/foo/v2", ServiceName: "foo.googleapis.com"},
+		{Path: "google/cloud/bar/v1", ServiceName: "bar.googleapis.com"},
+		{Path: "google/type"},
+		{Path: "google/rpc"},
+	}
+	err := ValidateServiceNames(apis)
+	if !errors.Is(err, ErrDuplicateServiceName) {
+		t.Fatalf("want error %v, got %v", ErrDuplicateServiceName, err)
+	}
+	want := "foo.googleapis.com (google/cloud/foo/v1, google/cloud/foo/v2)"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+	if strings.Contains(err.Error(), "bar.googleapis.com") {
+		t.Errorf("error %q reports a unique service name", err)
+	}
+}
@@ -108,18 +108,29 @@ func Find(googleapisDir, path string) (*API, error) {
 		return populateTitle(googleapisDir, result)
 	}
 
-	// Search filesystem for service config
-	dir := filepath.Join(googleapisDir, result.Path)
-	_, err := os.Stat(dir)
+	serviceConfig, err := FindServiceConfig(googleapisDir, result.Path)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return result, nil
-		}
 		return nil, err
 	}
+	if serviceConfig == "" {
+		return result, nil
+	}
+	result.ServiceConfig = serviceConfig
+	return populateTitle(googleapisDir, result)
+}
+
+// FindServiceConfig searches the directory of the API at path for a YAML file
+// containing "type: google.api.Service", skipping any files ending in
+// _gapic.yaml. It returns the path of the file relative to googleapisDir, or
+// an empty string if the directory does not exist or has no service config.
+func FindServiceConfig(googleapisDir, path string) (string, error) {
+	dir := filepath.Join(googleapisDir, path)
 	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
 	}
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -132,18 +143,15 @@ func Find(googleapisDir, path string) (*API, error) {
 		if strings.HasSuffix(name, "_gapic.yaml") {
 			continue
 		}
-
-		filePath := filepath.Join(dir, name)
-		isServiceConfig, err := isServiceConfigFile(filePath)
+		isServiceConfig, err := isServiceConfigFile(filepath.Join(dir, name))
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		if isServiceConfig {
-			result.ServiceConfig = filepath.Join(result.Path, name)
-			return populateTitle(googleapisDir, result)
+			return filepath.Join(path, name), nil
 		}
 	}
-	return result, nil
+	return "", nil
 }
 
 func populateTitle(googleapisDir string, api *API) (*API, error) {
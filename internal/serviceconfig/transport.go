@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceconfig
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// TransportRule constrains the transports that languages declare, in
+// API.Transports, for a group of APIs.
+type TransportRule struct {
+	// Prefix selects the APIs whose path is Prefix or is inside the
+	// directory Prefix, such as "google/cloud/bigquery/storage".
+	Prefix string
+
+	// Transport is the transport that every language must use, such as
+	// "grpc". If empty, every language must use the same transport, whatever
+	// it is.
+	Transport string
+}
+
+// TransportViolation describes a language whose transport for an API breaks
+// a TransportRule.
+type TransportViolation struct {
+	// Path is the API path.
+	Path string
+
+	// Language is the language whose transport breaks the rule.
+	Language string
+
+	// Transport is the transport the language declares.
+	Transport string
+
+	// Want is the transport the rule expects.
+	Want string
+}
+
+// TransportConsistency lists the APIs whose transports should match across
+// languages. A divergence is almost always a mistake in BUILD.bazel or in
+// the allowlist.
+var TransportConsistency = []TransportRule{
+	// The read and write APIs are streaming and only served over gRPC.
+	{Prefix: "google/cloud/bigquery/storage", Transport: "grpc"},
+}
+
+// CheckTransports returns the languages of apis whose transports break
+// rules. An API is checked against the rule with the longest matching prefix.
+// For a rule without a transport, the most common transport of the API is
+// expected, and ties go to the transport that sorts first.
+func CheckTransports(apis []API, rules []TransportRule) []TransportViolation {
+	var violations []TransportViolation
+	for _, api := range apis {
+		rule, ok := transportRule(api.Path, rules)
+		if !ok || len(api.Transports) == 0 {
+			continue
+		}
+		want := rule.Transport
+		if want == "" {
+			want = commonTransport(api.Transports)
+		}
+		for _, language := range slices.Sorted(maps.Keys(api.Transports)) {
+			if got := api.Transports[language]; got != want {
+				violations = append(violations, TransportViolation{
+					Path:      api.Path,
+					Language:  language,
+					Transport: got,
+					Want:      want,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// transportRule returns the rule in rules with the longest prefix matching
+// path.
+func transportRule(path string, rules []TransportRule) (TransportRule, bool) {
+	var match TransportRule
+	found := false
+	for _, rule := range rules {
+		if path != rule.Prefix && !strings.HasPrefix(path, rule.Prefix+"/") {
+			continue
+		}
+		if !found || len(rule.Prefix) > len(match.Prefix) {
+			match, found = rule, true
+		}
+	}
+	return match, found
+}
+
+// commonTransport returns the transport used by the most languages in
+// transports, preferring the one that sorts first on a tie.
+func commonTransport(transports map[string]string) string {
+	counts := make(map[string]int)
+	for _, t := range transports {
+		counts[t]++
+	}
+	var best string
+	for _, t := range slices.Sorted(maps.Keys(counts)) {
+		if counts[t] > counts[best] {
+			best = t
+		}
+	}
+	return best
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceconfig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckTransports(t *testing.T) {
+	rules := []TransportRule{
+		{Prefix: "google/cloud/bigquery/storage", Transport: "grpc"},
+		{Prefix: "google/cloud/speech"},
+		{Prefix: "google/cloud/speech/v2", Transport: "grpc+rest"},
+	}
+	for _, test := range []struct {
+		name string
+		apis []API
+		want []TransportViolation
+	}{
+		{
+			name: "streaming API set to rest for one language",
+			apis: []API{{
+				Path:       "google/cloud/bigquery/storage/v1",
+				Transports: map[string]string{"go": "grpc", "python": "grpc", "rust": "rest"},
+			}},
+			want: []TransportViolation{
+				{Path: "google/cloud/bigquery/storage/v1", Language: "rust", Transport: "rest", Want: "grpc"},
+			},
+		},
+		{
+			name: "consistent",
+			apis: []API{{
+				Path:       "google/cloud/bigquery/storage/v1",
+				Transports: map[string]string{"go": "grpc", "python": "grpc"},
+			}},
+		},
+		{
+			name: "mixed without a required transport",
+			apis: []API{{
+				Path:       "google/cloud/speech/v1",
+				Transports: map[string]string{"go": "grpc+rest", "java": "grpc+rest", "python": "grpc"},
+			}},
+			want: []TransportViolation{
+				{Path: "google/cloud/speech/v1", Language: "python", Transport: "grpc", Want: "grpc+rest"},
+			},
+		},
+		{
+			name: "longest prefix wins",
+			apis: []API{{
+				Path:       "google/cloud/speech/v2",
+				Transports: map[string]string{"go": "grpc", "python": "grpc"},
+			}},
+			want: []TransportViolation{
+				{Path: "google/cloud/speech/v2", Language: "go", Transport: "grpc", Want: "grpc+rest"},
+				{Path: "google/cloud/speech/v2", Language: "python", Transport: "grpc", Want: "grpc+rest"},
+			},
+		},
+		{
+			name: "no matching rule",
+			apis: []API{{
+				Path:       "google/cloud/speechless/v1",
+				Transports: map[string]string{"go": "grpc", "rust": "rest"},
+			}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := CheckTransports(test.apis, rules)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}